@@ -0,0 +1,9 @@
+data "linuxhost_network_interface" "uplink" {
+  name = "eth0"
+}
+
+resource "linuxhost_if_vlan" "vlan10" {
+  name   = "eth0.10"
+  vid    = 10
+  parent = data.linuxhost_network_interface.uplink.name
+}
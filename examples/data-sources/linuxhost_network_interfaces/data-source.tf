@@ -0,0 +1,9 @@
+data "linuxhost_network_interfaces" "vlans" {
+  type       = "vlan"
+  state      = "up"
+  name_regex = "^eth0\\."
+}
+
+output "vlan_ids" {
+  value = [for iface in data.linuxhost_network_interfaces.vlans.interfaces : iface.vlan.id]
+}
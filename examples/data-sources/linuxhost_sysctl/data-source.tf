@@ -0,0 +1,7 @@
+data "linuxhost_sysctl" "rp_filter" {
+  key = "net.ipv4.conf.all.rp_filter"
+}
+
+output "rp_filter" {
+  value = data.linuxhost_sysctl.rp_filter.value
+}
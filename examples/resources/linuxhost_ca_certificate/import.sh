@@ -0,0 +1,2 @@
+# The name of /usr/local/share/ca-certificates/<name>.crt
+terraform import linuxhost_ca_certificate.internal internal-root
@@ -0,0 +1 @@
+terraform import linuxhost_ethtool.uplink enp65s0f0
@@ -0,0 +1,27 @@
+resource "linuxhost_ethtool" "uplink" {
+  dev = "enp65s0f0"
+
+  features = {
+    gro = true
+    tso = true
+    lro = false
+  }
+
+  ring = {
+    rx = 8192
+    tx = 8192
+  }
+
+  channels = {
+    combined = 16
+  }
+
+  coalesce = {
+    adaptive_rx = false
+    rx_usecs    = 50
+  }
+
+  speed   = 25000
+  duplex  = "full"
+  autoneg = false
+}
@@ -0,0 +1 @@
+terraform import linuxhost_group.admins admins
@@ -0,0 +1 @@
+terraform import linuxhost_hosts_entry.registry 10.0.0.5
@@ -0,0 +1,5 @@
+resource "linuxhost_hosts_entry" "registry" {
+  ip        = "10.0.0.5"
+  hostnames = ["registry.lab", "registry"]
+  comment   = "lab container registry"
+}
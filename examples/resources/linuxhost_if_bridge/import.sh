@@ -0,0 +1,2 @@
+# Interfaces in a network namespace are imported as <netns>/<name>
+terraform import linuxhost_if_bridge.br0 br0
@@ -0,0 +1 @@
+terraform import linuxhost_if_dummy.dummy0 dummy0
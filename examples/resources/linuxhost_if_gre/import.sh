@@ -0,0 +1 @@
+terraform import linuxhost_if_gre.gre1 gre1
@@ -0,0 +1 @@
+terraform import linuxhost_if_gretap.gretap1 gretap1
@@ -0,0 +1 @@
+terraform import linuxhost_if_ipip.ipip1 ipip1
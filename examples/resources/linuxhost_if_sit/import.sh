@@ -0,0 +1 @@
+terraform import linuxhost_if_sit.sit1 sit1
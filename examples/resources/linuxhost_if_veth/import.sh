@@ -0,0 +1,2 @@
+# <local>:<peer>, either end may be <netns>/<name>
+terraform import linuxhost_if_veth.pair veth0:veth1
@@ -0,0 +1 @@
+terraform import linuxhost_if_vlan.vlan100 eth0.100
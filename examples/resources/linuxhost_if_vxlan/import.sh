@@ -0,0 +1 @@
+terraform import linuxhost_if_vxlan.vxlan42 vxlan42
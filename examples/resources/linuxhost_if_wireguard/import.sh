@@ -0,0 +1 @@
+terraform import linuxhost_if_wireguard.wg0 wg0
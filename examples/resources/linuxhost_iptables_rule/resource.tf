@@ -0,0 +1,18 @@
+resource "linuxhost_iptables_rule" "ssh" {
+  chain    = "INPUT"
+  rule     = "-p tcp --dport 22 -j ACCEPT"
+  position = 1
+}
+
+resource "linuxhost_iptables_rule" "ssh6" {
+  chain    = "INPUT"
+  rule     = "-p tcp --dport 22 -j ACCEPT"
+  position = 1
+  ipv6     = true
+}
+
+resource "linuxhost_iptables_rule" "masquerade" {
+  table = "nat"
+  chain = "POSTROUTING"
+  rule  = "-s 10.0.0.0/8 -o eth0 -j MASQUERADE"
+}
@@ -0,0 +1 @@
+terraform import linuxhost_network_interface.dummy dummy0
@@ -0,0 +1,2 @@
+# <interface>/<address>
+terraform import linuxhost_network_interface_ip.uplink eth0/192.0.2.10/24
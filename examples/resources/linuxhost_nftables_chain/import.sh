@@ -0,0 +1 @@
+terraform import linuxhost_nftables_chain.input inet/filter/input
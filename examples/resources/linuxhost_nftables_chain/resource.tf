@@ -0,0 +1,20 @@
+resource "linuxhost_nftables_table" "filter" {
+  family = "inet"
+  name   = "filter"
+}
+
+resource "linuxhost_nftables_chain" "input" {
+  family   = linuxhost_nftables_table.filter.family
+  table    = linuxhost_nftables_table.filter.name
+  name     = "input"
+  type     = "filter"
+  hook     = "input"
+  priority = 0
+  policy   = "drop"
+}
+
+resource "linuxhost_nftables_chain" "ssh" {
+  family = linuxhost_nftables_table.filter.family
+  table  = linuxhost_nftables_table.filter.name
+  name   = "ssh"
+}
@@ -0,0 +1,21 @@
+resource "linuxhost_nftables_rule" "established" {
+  family = "inet"
+  table  = "filter"
+  chain  = "input"
+  rule   = "ct state established,related accept"
+  insert = true
+}
+
+resource "linuxhost_nftables_rule" "ssh" {
+  family = "inet"
+  table  = "filter"
+  chain  = "input"
+  rule   = "tcp dport 22 accept"
+}
+
+resource "linuxhost_nftables_rule" "masquerade" {
+  family = "ip"
+  table  = "nat"
+  chain  = "postrouting"
+  rule   = "oifname \"eth0\" ip saddr 10.0.0.0/8 masquerade"
+}
@@ -0,0 +1 @@
+terraform import linuxhost_nftables_table.filter inet/filter
@@ -0,0 +1,4 @@
+resource "linuxhost_nftables_table" "filter" {
+  family = "inet"
+  name   = "filter"
+}
@@ -0,0 +1 @@
+terraform import linuxhost_sysctl.ip_forward net.ipv4.ip_forward
@@ -0,0 +1,11 @@
+resource "linuxhost_sysctl" "ip_forward" {
+  key     = "net.ipv4.ip_forward"
+  value   = "1"
+  persist = true
+}
+
+resource "linuxhost_sysctl" "bridge_nf" {
+  key     = "net.bridge.bridge-nf-call-iptables"
+  value   = "0"
+  persist = true
+}
@@ -0,0 +1 @@
+terraform import linuxhost_tc_class.bulk eth0/1:20
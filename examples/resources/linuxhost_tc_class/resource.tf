@@ -0,0 +1,22 @@
+resource "linuxhost_tc_qdisc" "uplink" {
+  dev     = "eth0"
+  handle  = "1:"
+  kind    = "htb"
+  options = "default 20"
+}
+
+resource "linuxhost_tc_class" "total" {
+  dev     = linuxhost_tc_qdisc.uplink.dev
+  parent  = linuxhost_tc_qdisc.uplink.handle
+  classid = "1:1"
+  kind    = "htb"
+  options = "rate 1gbit"
+}
+
+resource "linuxhost_tc_class" "bulk" {
+  dev     = linuxhost_tc_qdisc.uplink.dev
+  parent  = linuxhost_tc_class.total.classid
+  classid = "1:20"
+  kind    = "htb"
+  options = "rate 200mbit ceil 1gbit"
+}
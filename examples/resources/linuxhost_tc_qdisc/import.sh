@@ -0,0 +1 @@
+terraform import linuxhost_tc_qdisc.uplink eth0/root
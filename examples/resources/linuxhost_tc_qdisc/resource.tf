@@ -0,0 +1,25 @@
+# Emulate a WAN link
+resource "linuxhost_tc_qdisc" "wan" {
+  dev     = "eth1"
+  kind    = "netem"
+  options = "delay 50ms 10ms loss 0.5%"
+}
+
+# Shape the uplink
+resource "linuxhost_tc_qdisc" "uplink" {
+  dev     = "eth0"
+  handle  = "1:"
+  kind    = "htb"
+  options = "default 20"
+}
+
+resource "linuxhost_tc_qdisc" "uplink_bulk" {
+  dev    = "eth0"
+  parent = "1:20"
+  kind   = "fq_codel"
+}
+
+resource "linuxhost_tc_qdisc" "clsact" {
+  dev    = "eth0"
+  parent = "clsact"
+}
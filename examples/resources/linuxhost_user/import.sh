@@ -0,0 +1 @@
+terraform import linuxhost_user.alice alice
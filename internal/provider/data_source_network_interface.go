@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &NetworkInterfaceDataSource{}
+
+func NewNetworkInterfaceDataSource() datasource.DataSource {
+	return &NetworkInterfaceDataSource{}
+}
+
+// NetworkInterfaceDataSource looks up an existing interface by name or MAC
+type NetworkInterfaceDataSource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (d *NetworkInterfaceDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network_interface"
+}
+
+// networkInterfaceDataAttributes are the attributes of an interface, lookup
+// makes name and mac configurable to select it
+func networkInterfaceDataAttributes(lookup bool) map[string]schema.Attribute {
+	return map[string]schema.Attribute{
+		"name": schema.StringAttribute{
+			Optional:            lookup,
+			Computed:            true,
+			MarkdownDescription: "The name of the interface",
+		},
+		"mac": schema.StringAttribute{
+			Optional:            lookup,
+			Computed:            true,
+			MarkdownDescription: "The MAC address of the interface",
+		},
+		"type": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The type of the interface: `bridge`, `dummy`, `veth`, `wireguard`, `vlan`, `vxlan`, `gre`, `gretap`, `ipip`, `sit` or `unknown` for others like physical interfaces",
+		},
+		"mtu": schema.Int32Attribute{
+			Computed:            true,
+			MarkdownDescription: "The MTU of the interface",
+		},
+		"state": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "`up` or `down`",
+		},
+		"ipv4": schema.ListAttribute{
+			ElementType:         types.StringType,
+			Computed:            true,
+			MarkdownDescription: "The IPv4 addresses with their prefix length",
+		},
+		"ipv6": schema.ListAttribute{
+			ElementType:         types.StringType,
+			Computed:            true,
+			MarkdownDescription: "The IPv6 addresses with their prefix length",
+		},
+		"parent": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The interface this one is linked to, like the parent of a VLAN",
+		},
+		"bridge": schema.StringAttribute{
+			Computed:            true,
+			MarkdownDescription: "The bridge the interface is a member of",
+		},
+		"vlan": schema.SingleNestedAttribute{
+			Computed:            true,
+			MarkdownDescription: "The VLAN details, null for other types",
+			Attributes: map[string]schema.Attribute{
+				"id": schema.Int32Attribute{
+					Computed:            true,
+					MarkdownDescription: "The VLAN id",
+				},
+				"parent": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "The parent interface",
+				},
+			},
+		},
+		"vxlan": schema.SingleNestedAttribute{
+			Computed:            true,
+			MarkdownDescription: "The VXLAN details, null for other types",
+			Attributes: map[string]schema.Attribute{
+				"vni": schema.Int64Attribute{
+					Computed:            true,
+					MarkdownDescription: "The VXLAN network identifier",
+				},
+				"port": schema.Int32Attribute{
+					Computed:            true,
+					MarkdownDescription: "The destination UDP port",
+				},
+				"local": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "The source address",
+				},
+				"remote": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "The unicast destination",
+				},
+				"group": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "The multicast group",
+				},
+				"dev": schema.StringAttribute{
+					Computed:            true,
+					MarkdownDescription: "The underlay interface",
+				},
+				"learning": schema.BoolAttribute{
+					Computed:            true,
+					MarkdownDescription: "Whether source addresses are learned",
+				},
+			},
+		},
+	}
+}
+
+func (d *NetworkInterfaceDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "An existing network interface of the host, looked up by `name` or `mac`.",
+		Attributes:          networkInterfaceDataAttributes(true),
+	}
+}
+
+func (d *NetworkInterfaceDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	hostData, ok := req.ProviderData.(*linuxhost_client.HostData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *linuxhost_client.HostData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.hostData = hostData
+}
+
+func (d *NetworkInterfaceDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.NetworkInterfaceDataModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if data.Name.IsNull() == data.Mac.IsNull() {
+		resp.Diagnostics.AddError("Invalid lookup", "Exactly one of name and mac has to be specified.")
+		return
+	}
+
+	adapters, err := linuxhost_client.RefreshAdapters(d.hostData)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read interfaces", err.Error())
+		return
+	}
+	var found *linuxhost_client.AdapterInfo
+	for _, adapter := range adapters {
+		if adapter.Name == data.Name.ValueString() || (!data.Mac.IsNull() && strings.EqualFold(adapter.MAC, data.Mac.ValueString())) {
+			found = adapter
+			break
+		}
+	}
+	if found == nil {
+		resp.Diagnostics.AddError("Interface not found", fmt.Sprintf("No interface with name %s or mac %s exists.", data.Name.ValueString(), data.Mac.ValueString()))
+		return
+	}
+
+	result, diags := networkInterfaceToData(ctx, &adapters, found)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &result)...)
+}
+
+// networkInterfaceToData converts an adapter to the data source model
+func networkInterfaceToData(ctx context.Context, adapters *linuxhost_client.AdapterInfoSlice, a *linuxhost_client.AdapterInfo) (models.NetworkInterfaceDataModel, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	state := "down"
+	if a.Up {
+		state = "up"
+	}
+	ipv4, d := types.ListValueFrom(ctx, types.StringType, models.IPList(a.IPv4))
+	diags.Append(d...)
+	ipv6, d := types.ListValueFrom(ctx, types.StringType, models.IPList(a.IPv6))
+	diags.Append(d...)
+
+	data := models.NetworkInterfaceDataModel{
+		Name:   types.StringValue(a.Name),
+		Mac:    nonEmptyStringOrNull(a.MAC),
+		Type:   types.StringValue(a.Type),
+		Mtu:    types.Int32Null(),
+		State:  types.StringValue(state),
+		IP4s:   ipv4,
+		IP6s:   ipv6,
+		Parent: types.StringNull(),
+		Bridge: types.StringNull(),
+	}
+	if a.Mtu != 0 {
+		data.Mtu = int32OrNull(a.Mtu)
+	}
+	if a.LinkedInterface != nil {
+		data.Parent = nonEmptyStringOrNull(*a.LinkedInterface)
+	}
+	if a.DesignatedBridge != nil {
+		if bridge := adapters.GetIsBridgeId(*a.DesignatedBridge); bridge != nil {
+			data.Bridge = types.StringValue(bridge.Name)
+		}
+	}
+	if a.VlanInfo != nil {
+		data.Vlan = &models.NetworkInterfaceVlanDataModel{
+			Id:     int32OrNull(a.VlanInfo.Vid),
+			Parent: nonEmptyStringOrNull(a.VlanInfo.Parent),
+		}
+	}
+	if a.VxlanInfo != nil {
+		data.Vxlan = &models.NetworkInterfaceVxlanDataModel{
+			Vni:      int64OrNull(a.Vni),
+			Port:     int32OrNull(a.Port),
+			Local:    nonEmptyStringOrNull(a.VxlanInfo.Local),
+			Remote:   nonEmptyStringOrNull(a.VxlanInfo.Remote),
+			Group:    nonEmptyStringOrNull(a.VxlanInfo.Group),
+			Dev:      nonEmptyStringOrNull(a.VxlanInfo.Dev),
+			Learning: types.BoolValue(a.VxlanInfo.Learning),
+		}
+	}
+	return data, diags
+}
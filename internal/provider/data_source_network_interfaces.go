@@ -0,0 +1,117 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+var _ datasource.DataSourceWithConfigure = &NetworkInterfacesDataSource{}
+
+func NewNetworkInterfacesDataSource() datasource.DataSource {
+	return &NetworkInterfacesDataSource{}
+}
+
+// NetworkInterfacesDataSource lists the interfaces matching the filters
+type NetworkInterfacesDataSource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (d *NetworkInterfacesDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_network_interfaces"
+}
+
+func (d *NetworkInterfacesDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The network interfaces of the host, optionally filtered.",
+		Attributes: map[string]schema.Attribute{
+			"type": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list interfaces of this type: `bridge`, `dummy`, `veth`, `wireguard`, `vlan`, `vxlan`, `gre`, `gretap`, `ipip`, `sit` or `unknown`",
+			},
+			"state": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list interfaces that are `up` or `down`",
+				Validators: []validator.String{
+					stringvalidator.OneOf("up", "down"),
+				},
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only list interfaces whose name matches this regular expression",
+			},
+			"interfaces": schema.ListNestedAttribute{
+				Computed:            true,
+				MarkdownDescription: "The matching interfaces",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: networkInterfaceDataAttributes(false),
+				},
+			},
+		},
+	}
+}
+
+func (d *NetworkInterfacesDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	hostData, ok := req.ProviderData.(*linuxhost_client.HostData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *linuxhost_client.HostData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.hostData = hostData
+}
+
+func (d *NetworkInterfacesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.NetworkInterfacesDataModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	var nameRegex *regexp.Regexp
+	if !data.NameRegex.IsNull() {
+		var err error
+		nameRegex, err = regexp.Compile(data.NameRegex.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("name_regex"), "Invalid regular expression", err.Error())
+			return
+		}
+	}
+
+	adapters, err := linuxhost_client.RefreshAdapters(d.hostData)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read interfaces", err.Error())
+		return
+	}
+	data.Interfaces = []models.NetworkInterfaceDataModel{}
+	for _, adapter := range adapters {
+		if nameRegex != nil && !nameRegex.MatchString(adapter.Name) {
+			continue
+		}
+		iface, diags := networkInterfaceToData(ctx, &adapters, adapter)
+		resp.Diagnostics.Append(diags...)
+		if !data.Type.IsNull() && iface.Type.ValueString() != data.Type.ValueString() {
+			continue
+		}
+		if !data.State.IsNull() && iface.State.ValueString() != data.State.ValueString() {
+			continue
+		}
+		data.Interfaces = append(data.Interfaces, iface)
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
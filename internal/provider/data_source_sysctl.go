@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ datasource.DataSourceWithConfigure = &SysctlDataSource{}
+
+func NewSysctlDataSource() datasource.DataSource {
+	return &SysctlDataSource{}
+}
+
+// SysctlDataSource reads the runtime value of a kernel parameter
+type SysctlDataSource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (d *SysctlDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sysctl"
+}
+
+func (d *SysctlDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The runtime value of a kernel parameter, as reported by `sysctl -n`.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The parameter, e.g. 'net.ipv4.ip_forward'",
+				Validators: []validator.String{
+					sysctlKeyValidator,
+				},
+			},
+			"value": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The value, multiple fields are separated by spaces",
+			},
+		},
+	}
+}
+
+func (d *SysctlDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+	hostData, ok := req.ProviderData.(*linuxhost_client.HostData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *linuxhost_client.HostData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+	d.hostData = hostData
+}
+
+func (d *SysctlDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data models.SysctlDataModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	value, err := linuxhost_client.ReadSysctl(d.hostData.Client, data.Key.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed reading sysctl", err.Error())
+		return
+	}
+	if value == nil {
+		resp.Diagnostics.AddError("Sysctl not found", fmt.Sprintf("The kernel parameter %s does not exist.", data.Key.ValueString()))
+		return
+	}
+	data.Value = types.StringValue(*value)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
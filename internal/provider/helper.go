@@ -28,6 +28,14 @@ func stringOrNull(value interface{}) types.String {
 	}
 }
 
+// nonEmptyStringOrNull treats an empty string as unset
+func nonEmptyStringOrNull(value string) types.String {
+	if value == "" {
+		return types.StringNull()
+	}
+	return types.StringValue(value)
+}
+
 func numberOrNull(value interface{}) types.Number {
 	fmt.Println("Checking number!")
 	fmt.Println(value)
@@ -126,3 +134,21 @@ func (r ReadableResource[M]) InState(target M, expect string) {
 		r.Diagnostics.AddError("Invalid expectation", "This is an error with the provider 'linuxhost'")
 	}
 }
+
+// uint32OrNil returns nil for null or unknown values, so optional settings
+// can be left untouched on the host.
+func uint32OrNil(value types.Int32) *uint32 {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	v := uint32(value.ValueInt32())
+	return &v
+}
+
+func boolOrNil(value types.Bool) *bool {
+	if value.IsNull() || value.IsUnknown() {
+		return nil
+	}
+	v := value.ValueBool()
+	return &v
+}
@@ -9,10 +9,12 @@ import (
 
 	"terraform-provider-linuxhost/linuxhost_client"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
@@ -40,6 +42,9 @@ func (p *linuxHostProvider) Configure(ctx context.Context, req provider.Configur
 		Password   *string `tfsdk:"password"`
 		PrivateKey *string `tfsdk:"private_key"`
 		Port       *int64  `tfsdk:"port"`
+
+		NetplanApply   *bool   `tfsdk:"netplan_apply"`
+		NetworkBackend *string `tfsdk:"network_backend"`
 	}
 
 	diags := req.Config.Get(ctx, &config)
@@ -84,7 +89,11 @@ func (p *linuxHostProvider) Configure(ctx context.Context, req provider.Configur
 	tflog.Info(ctx, "Should now be connected")
 
 	hostData := &linuxhost_client.HostData{
-		Client: clientContext,
+		Client:       clientContext,
+		NetplanApply: config.NetplanApply != nil && *config.NetplanApply,
+	}
+	if config.NetworkBackend != nil {
+		hostData.NetworkBackend = *config.NetworkBackend
 	}
 
 	resp.DataSourceData = hostData
@@ -94,7 +103,11 @@ func (p *linuxHostProvider) Configure(ctx context.Context, req provider.Configur
 }
 
 func (p *linuxHostProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewNetworkInterfaceDataSource,
+		NewNetworkInterfacesDataSource,
+		NewSysctlDataSource,
+	}
 }
 
 func (p *linuxHostProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
@@ -111,9 +124,29 @@ func (p *linuxHostProvider) Resources(ctx context.Context) []func() resource.Res
 		NewGroupResource,
 		NewCaCertificateResource,
 		NewIfBridgeResource,
+		NewBridgeVlanResource,
+		NewBridgeFdbResource,
+		NewIfDummyResource,
+		NewIfGreResource,
+		NewIfGretapResource,
+		NewIfIpipResource,
+		NewIfSitResource,
 		NewIfVethResource,
 		NewIfVlanResource,
 		NewIfVxlanResource,
+		NewIfWireguardResource,
+		NewWireguardPeerResource,
+		NewNeighborResource,
+		NewNetnsResource,
+		NewEthtoolResource,
+		NewSysctlResource,
+		NewNftablesTableResource,
+		NewNftablesChainResource,
+		NewNftablesRuleResource,
+		NewIptablesRuleResource,
+		NewTcQdiscResource,
+		NewTcClassResource,
+		NewHostsEntryResource,
 	}
 }
 
@@ -142,6 +175,17 @@ func (p *linuxHostProvider) Schema(ctx context.Context, req provider.SchemaReque
 				Description: "The SSH port to connect to.",
 				Optional:    true,
 			},
+			"network_backend": schema.StringAttribute{
+				Description: "How interfaces are created. Valid options: 'iproute2' (default), which uses `ip link`, and 'nmcli', which adds NetworkManager connection profiles for bridges, dummies, VLANs, VXLANs, tunnels and veths and adds IP addresses to the profiles. Use 'nmcli' on hosts where NetworkManager would tear down interfaces it doesn't know.",
+				Optional:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("iproute2", "nmcli"),
+				},
+			},
+			"netplan_apply": schema.BoolAttribute{
+				Description: "Whether to run `netplan apply` after resources with `persistence = \"netplan\"` changed the netplan configuration. If applying fails the previous configuration is restored. Defaults to false, the configuration then takes effect on the next reboot.",
+				Optional:    true,
+			},
 		},
 	}
 }
@@ -0,0 +1,248 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &BridgeFdbResource{}
+var _ resource.ResourceWithValidateConfig = &BridgeFdbResource{}
+
+func NewBridgeFdbResource() resource.Resource {
+	return &BridgeFdbResource{}
+}
+
+type BridgeFdbResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *BridgeFdbResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bridge_fdb"
+}
+
+func (r *BridgeFdbResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A static forwarding database entry. Use the MAC `00:00:00:00:00:00` with `dst` to add a VXLAN head-end replication destination.",
+		Attributes: map[string]schema.Attribute{
+			"mac": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The MAC address of the entry, e.g. '52:54:00:aa:bb:cc'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"dev": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The interface the entry is associated with, e.g. 'vxlan0'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"dst": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The IP address of the remote VTEP. Only supported on VXLAN devices.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vni": schema.Int32Attribute{
+				Optional:            true,
+				MarkdownDescription: "The VNI used to reach `dst`, if it differs from the device's VNI",
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int32{
+					int32validator.Between(1, 16777215),
+				},
+			},
+			"port": schema.Int32Attribute{
+				Optional:            true,
+				MarkdownDescription: "The UDP port used to reach `dst`, if it differs from the device's port",
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int32{
+					int32validator.Between(1, 65535),
+				},
+			},
+			"master": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Add the entry to the forwarding database of the bridge `dev` belongs to",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"self": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Add the entry to the forwarding database of `dev` itself, as needed for VXLAN destinations",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"state": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("permanent"),
+				MarkdownDescription: "The entry state. Valid options: 'permanent', 'static'.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("permanent", "static"),
+				},
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *BridgeFdbResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *BridgeFdbResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config models.BridgeFdbModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.Dst.IsNull() && (!config.Vni.IsNull() || !config.Port.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dst"),
+			"Missing dst",
+			"The 'vni' and 'port' attributes require 'dst'.",
+		)
+	}
+}
+
+func bridgeFdbFromModel(data *models.BridgeFdbModel) *linuxhost_client.BridgeFdb {
+	return &linuxhost_client.BridgeFdb{
+		Mac:    data.Mac.ValueString(),
+		Dev:    data.Dev.ValueString(),
+		Dst:    data.Dst.ValueString(),
+		Vni:    uint32(data.Vni.ValueInt32()),
+		Port:   uint32(data.Port.ValueInt32()),
+		Master: data.Master.ValueBool(),
+		Self:   data.Self.ValueBool(),
+		State:  data.State.ValueString(),
+	}
+}
+
+func (r *BridgeFdbResource) readState(ctx context.Context, data *models.BridgeFdbModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
+	fdbs, err := linuxhost_client.ReadBridgeFdb(r.hostData.Client, data.Dev.ValueString())
+	if err != nil {
+		Diagnostics.AddError("Failed reading bridge fdb", err.Error())
+		return
+	}
+	found := linuxhost_client.FindBridgeFdb(fdbs, bridgeFdbFromModel(data))
+	if found != nil {
+		if expect == "absent" {
+			Diagnostics.AddError("Failed to delete", "The delete operation did not report any errors but the resource remains present in the reported state.")
+			return
+		}
+		// The kernel only reports vni and port if they differ from the device
+		current := &models.BridgeFdbModel{
+			Mac:    data.Mac,
+			Dev:    data.Dev,
+			Dst:    data.Dst,
+			Vni:    data.Vni,
+			Port:   data.Port,
+			Master: data.Master,
+			Self:   data.Self,
+			State:  types.StringValue(found.State),
+		}
+		Diagnostics.Append(State.Set(ctx, current)...)
+		return
+	}
+	if expect == "present" {
+		Diagnostics.AddError("Didn't find bridge fdb entry", "")
+	} else if expect == "any" || expect == "absent" {
+		State.RemoveResource(ctx)
+	} else {
+		Diagnostics.AddError("Invalid expectation", "This is an error with the provider 'linuxhost'")
+	}
+}
+
+func (r *BridgeFdbResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.BridgeFdbModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetBridgeFdb(r.hostData.Client, bridgeFdbFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed creating bridge fdb entry", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *BridgeFdbResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.BridgeFdbModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "any")
+}
+
+func (r *BridgeFdbResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.BridgeFdbModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetBridgeFdb(r.hostData.Client, bridgeFdbFromModel(&plan)); err != nil {
+		resp.Diagnostics.AddError("Failed updating bridge fdb entry", err.Error())
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *BridgeFdbResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.BridgeFdbModel
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The entries are gone with their device
+	fdbs, err := linuxhost_client.ReadBridgeFdb(r.hostData.Client, data.Dev.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed reading bridge fdb", err.Error())
+		return
+	}
+	if linuxhost_client.FindBridgeFdb(fdbs, bridgeFdbFromModel(&data)) == nil {
+		return
+	}
+	if err := linuxhost_client.DeleteBridgeFdb(r.hostData.Client, bridgeFdbFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed to delete bridge fdb entry", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "absent")
+}
@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &BridgeVlanResource{}
+var _ resource.ResourceWithValidateConfig = &BridgeVlanResource{}
+
+func NewBridgeVlanResource() resource.Resource {
+	return &BridgeVlanResource{}
+}
+
+type BridgeVlanResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *BridgeVlanResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_bridge_vlan"
+}
+
+func (r *BridgeVlanResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A VLAN membership of a bridge port. The bridge must have `vlan_filtering` enabled.",
+		Attributes: map[string]schema.Attribute{
+			"port": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The bridge port, e.g. 'eth1'. Use the bridge itself together with `self` to configure the bridge's own VLANs.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"vid": schema.Int32Attribute{
+				Required:            true,
+				MarkdownDescription: "The VLAN ID, or the first VLAN ID of a range",
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int32{
+					int32validator.Between(1, 4094),
+				},
+			},
+			"vid_end": schema.Int32Attribute{
+				Optional:            true,
+				MarkdownDescription: "If specified, the last VLAN ID of a range starting at `vid`",
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int32{
+					int32validator.Between(1, 4094),
+				},
+			},
+			"pvid": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Whether untagged ingress traffic is assigned to this VLAN. Not supported for ranges.",
+			},
+			"untagged": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Whether egress traffic for this VLAN leaves the port untagged",
+			},
+			"self": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Set when `port` is the bridge device itself",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *BridgeVlanResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *BridgeVlanResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config models.BridgeVlanModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.VidEnd.IsNull() || config.VidEnd.IsUnknown() || config.Vid.IsUnknown() {
+		return
+	}
+	if config.VidEnd.ValueInt32() < config.Vid.ValueInt32() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("vid_end"),
+			"Invalid VLAN range",
+			"The 'vid_end' attribute must not be lower than 'vid'.",
+		)
+	}
+	if config.Pvid.ValueBool() && config.VidEnd.ValueInt32() != config.Vid.ValueInt32() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("pvid"),
+			"Unexpected pvid",
+			"A VLAN range cannot be the port's pvid.",
+		)
+	}
+}
+
+func bridgeVlanFromModel(data *models.BridgeVlanModel) *linuxhost_client.BridgeVlan {
+	return &linuxhost_client.BridgeVlan{
+		Port:     data.Port.ValueString(),
+		Vid:      uint32(data.Vid.ValueInt32()),
+		VidEnd:   uint32(data.VidEnd.ValueInt32()),
+		Pvid:     data.Pvid.ValueBool(),
+		Untagged: data.Untagged.ValueBool(),
+		Self:     data.Self.ValueBool(),
+	}
+}
+
+func (r *BridgeVlanResource) readState(ctx context.Context, data *models.BridgeVlanModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
+	vlans, err := linuxhost_client.ReadBridgeVlans(r.hostData.Client)
+	if err != nil {
+		Diagnostics.AddError("Failed reading bridge vlans", err.Error())
+		return
+	}
+	found := linuxhost_client.FindBridgeVlan(vlans, bridgeVlanFromModel(data))
+	if found != nil {
+		if expect == "absent" {
+			Diagnostics.AddError("Failed to delete", "The delete operation did not report any errors but the resource remains present in the reported state.")
+			return
+		}
+		current := &models.BridgeVlanModel{
+			Port:     data.Port,
+			Vid:      data.Vid,
+			VidEnd:   data.VidEnd,
+			Pvid:     types.BoolValue(found.Pvid),
+			Untagged: types.BoolValue(found.Untagged),
+			Self:     data.Self,
+		}
+		Diagnostics.Append(State.Set(ctx, current)...)
+		return
+	}
+	if expect == "present" {
+		Diagnostics.AddError("Didn't find bridge vlan", "")
+	} else if expect == "any" || expect == "absent" {
+		State.RemoveResource(ctx)
+	} else {
+		Diagnostics.AddError("Invalid expectation", "This is an error with the provider 'linuxhost'")
+	}
+}
+
+func (r *BridgeVlanResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.BridgeVlanModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetBridgeVlan(r.hostData.Client, bridgeVlanFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed creating bridge vlan", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *BridgeVlanResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.BridgeVlanModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "any")
+}
+
+func (r *BridgeVlanResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.BridgeVlanModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetBridgeVlan(r.hostData.Client, bridgeVlanFromModel(&plan)); err != nil {
+		resp.Diagnostics.AddError("Failed updating bridge vlan", err.Error())
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *BridgeVlanResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.BridgeVlanModel
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.DeleteBridgeVlan(r.hostData.Client, bridgeVlanFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed to delete bridge vlan", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "absent")
+}
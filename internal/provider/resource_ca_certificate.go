@@ -17,6 +17,8 @@ import (
 )
 
 var _ resource.ResourceWithConfigure = &CaCertificateResource{}
+var _ resource.ResourceWithImportState = &CaCertificateResource{}
+var _ resource.ResourceWithModifyPlan = &CaCertificateResource{}
 
 func NewCaCertificateResource() resource.Resource {
 	return &CaCertificateResource{}
@@ -37,6 +39,9 @@ func (r *CaCertificateResource) Schema(ctx context.Context, req resource.SchemaR
 			"name": schema.StringAttribute{
 				Required:            true,
 				MarkdownDescription: "Human-readable name for the certificate, also used as its filename",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
 			"source": schema.StringAttribute{
 				Required:            true,
@@ -44,8 +49,9 @@ func (r *CaCertificateResource) Schema(ctx context.Context, req resource.SchemaR
 			},
 			"fingerprint_sha256": schema.StringAttribute{
 				Computed: true,
+				// ModifyPlan replaces the resource if the certificate changed
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.UseStateForUnknown(),
 				},
 			},
 		},
@@ -58,7 +64,20 @@ func (r *CaCertificateResource) Configure(ctx context.Context, req resource.Conf
 }
 
 func (r *CaCertificateResource) readState(ctx context.Context, data *models.CaCertificateModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
-	expected := linuxhost_client.CertificateInfo(data.Source.ValueString())
+	var expected linuxhost_client.CertificateData
+	if data.Source.IsNull() {
+		// Imported, compare with the installed file
+		cert, err := linuxhost_client.ReadRemoteCaCertificate(r.hostData.Client, data.Name.ValueString())
+		if err != nil {
+			Diagnostics.AddError("Failed to read the installed certificate", err.Error())
+			return
+		}
+		if cert != nil {
+			expected.Sha256Fingerprint = linuxhost_client.Sha256Fingerprint(cert)
+		}
+	} else {
+		expected = linuxhost_client.CertificateInfo(data.Source.ValueString())
+	}
 
 	certs := linuxhost_client.RefreshRemoteCertificates(r.hostData.Client)
 
@@ -141,11 +160,31 @@ func (r *CaCertificateResource) Update(ctx context.Context, req resource.UpdateR
 		return
 	}
 
-	resp.Diagnostics.AddError("Not implemented", "Update is not implemented.")
-	// return
+	// Only the source changed to a file with the same certificate, e.g. after
+	// an import, other changes replace the resource
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
 
-	// Save updated data into Terraform state
-	// resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+func (r *CaCertificateResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+	var plan, state models.CaCertificateModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() || plan.Source.IsUnknown() || plan.Source.Equal(state.Source) {
+		return
+	}
+	info, err := linuxhost_client.ReadCertificateInfo(plan.Source.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("source"), "Invalid certificate source", err.Error())
+		return
+	}
+	fingerprint := linuxhost_client.EncodeBytesString(info.Sha256Fingerprint)
+	if fingerprint != state.FingerprintSha256.ValueString() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("fingerprint_sha256"), fingerprint)...)
+		resp.RequiresReplace = append(resp.RequiresReplace, path.Root("fingerprint_sha256"))
+	}
 }
 
 func (r *CaCertificateResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -167,6 +206,9 @@ func (r *CaCertificateResource) Delete(ctx context.Context, req resource.DeleteR
 
 	// resp.Diagnostics.AddError("Not implemented", "Delete is not implemented.")
 }
+
+// ImportState imports the certificate by name, the source is set by the next
+// apply without replacing the certificate if it has the same fingerprint
 func (r *CaCertificateResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
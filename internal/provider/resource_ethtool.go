@@ -0,0 +1,329 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &EthtoolResource{}
+var _ resource.ResourceWithImportState = &EthtoolResource{}
+
+func NewEthtoolResource() resource.Resource {
+	return &EthtoolResource{}
+}
+
+type EthtoolResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *EthtoolResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_ethtool"
+}
+
+func ethtoolCountAttribute(description string) schema.Int32Attribute {
+	return schema.Int32Attribute{
+		Optional:            true,
+		MarkdownDescription: description,
+		Validators: []validator.Int32{
+			int32validator.AtLeast(0),
+		},
+	}
+}
+
+func (r *EthtoolResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "NIC settings changed with ethtool: offloads, ring buffers, channels, interrupt coalescing and the link mode. " +
+			"Only the configured settings are managed and each one is read back to report drift. " +
+			"The settings aren't persisted across reboots and deleting the resource leaves them as they are.",
+		Attributes: map[string]schema.Attribute{
+			"dev": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The interface to configure, e.g. 'eth0'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"features": schema.MapAttribute{
+				ElementType:         types.BoolType,
+				Optional:            true,
+				MarkdownDescription: "The offloads to switch on or off, by the name `ethtool -k` reports like `generic-receive-offload` or by the short alias of `ethtool -K` like `gro`",
+			},
+			"ring": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "The ring buffer sizes (`ethtool -G`)",
+				Attributes: map[string]schema.Attribute{
+					"rx":       ethtoolCountAttribute("The RX ring size"),
+					"rx_mini":  ethtoolCountAttribute("The RX mini ring size"),
+					"rx_jumbo": ethtoolCountAttribute("The RX jumbo ring size"),
+					"tx":       ethtoolCountAttribute("The TX ring size"),
+				},
+			},
+			"channels": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "The channel counts (`ethtool -L`)",
+				Attributes: map[string]schema.Attribute{
+					"rx":       ethtoolCountAttribute("The number of RX only channels"),
+					"tx":       ethtoolCountAttribute("The number of TX only channels"),
+					"other":    ethtoolCountAttribute("The number of channels for other purposes like link interrupts"),
+					"combined": ethtoolCountAttribute("The number of combined RX and TX channels"),
+				},
+			},
+			"coalesce": schema.SingleNestedAttribute{
+				Optional:            true,
+				MarkdownDescription: "The interrupt coalescing (`ethtool -C`)",
+				Attributes: map[string]schema.Attribute{
+					"adaptive_rx": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Whether RX coalescing adapts to the traffic",
+					},
+					"adaptive_tx": schema.BoolAttribute{
+						Optional:            true,
+						MarkdownDescription: "Whether TX coalescing adapts to the traffic",
+					},
+					"rx_usecs":  ethtoolCountAttribute("Microseconds to delay an RX interrupt after a packet arrives"),
+					"rx_frames": ethtoolCountAttribute("Packets to receive before an RX interrupt"),
+					"tx_usecs":  ethtoolCountAttribute("Microseconds to delay a TX interrupt after a packet is sent"),
+					"tx_frames": ethtoolCountAttribute("Packets to send before a TX interrupt"),
+				},
+			},
+			"speed": schema.Int32Attribute{
+				Optional:            true,
+				MarkdownDescription: "The link speed in Mb/s, e.g. 25000",
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
+			},
+			"duplex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The duplex mode. Valid options: 'half', 'full'.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("half", "full"),
+				},
+			},
+			"autoneg": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: "Whether the link mode is auto-negotiated",
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *EthtoolResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *EthtoolResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("dev"), req, resp)
+}
+
+// ethtoolNumbers pair the ethtool parameters with the attributes of the
+// nested models, nil models have none
+func ethtoolRingNumbers(m *models.EthtoolRingModel) map[string]*types.Int32 {
+	if m == nil {
+		return nil
+	}
+	return map[string]*types.Int32{"rx": &m.Rx, "rx-mini": &m.RxMini, "rx-jumbo": &m.RxJumbo, "tx": &m.Tx}
+}
+
+func ethtoolChannelsNumbers(m *models.EthtoolChannelsModel) map[string]*types.Int32 {
+	if m == nil {
+		return nil
+	}
+	return map[string]*types.Int32{"rx": &m.Rx, "tx": &m.Tx, "other": &m.Other, "combined": &m.Combined}
+}
+
+func ethtoolCoalesceNumbers(m *models.EthtoolCoalesceModel) map[string]*types.Int32 {
+	if m == nil {
+		return nil
+	}
+	return map[string]*types.Int32{"rx-usecs": &m.RxUsecs, "rx-frames": &m.RxFrames, "tx-usecs": &m.TxUsecs, "tx-frames": &m.TxFrames}
+}
+
+func ethtoolNumbersFromModel(numbers map[string]*types.Int32) map[string]uint32 {
+	result := map[string]uint32{}
+	for key, value := range numbers {
+		if v := uint32OrNil(*value); v != nil {
+			result[key] = *v
+		}
+	}
+	return result
+}
+
+// ethtoolNumbersToState replaces the configured numbers with the current ones
+func ethtoolNumbersToState(numbers map[string]*types.Int32, current map[string]uint32) {
+	for key, value := range numbers {
+		if value.IsNull() {
+			continue
+		}
+		if v, ok := current[key]; ok {
+			*value = types.Int32Value(int32(v))
+		} else {
+			*value = types.Int32Null()
+		}
+	}
+}
+
+func ethtoolFromModel(data *models.EthtoolModel) *linuxhost_client.EthtoolSettings {
+	settings := &linuxhost_client.EthtoolSettings{
+		Features: map[string]bool{},
+		Ring:     ethtoolNumbersFromModel(ethtoolRingNumbers(data.Ring)),
+		Channels: ethtoolNumbersFromModel(ethtoolChannelsNumbers(data.Channels)),
+		Coalesce: ethtoolNumbersFromModel(ethtoolCoalesceNumbers(data.Coalesce)),
+		Duplex:   data.Duplex.ValueString(),
+		Autoneg:  boolOrNil(data.Autoneg),
+	}
+	for feature, value := range data.Features {
+		if !value.IsNull() && !value.IsUnknown() {
+			settings.Features[feature] = value.ValueBool()
+		}
+	}
+	if data.Coalesce != nil {
+		settings.AdaptiveRx = boolOrNil(data.Coalesce.AdaptiveRx)
+		settings.AdaptiveTx = boolOrNil(data.Coalesce.AdaptiveTx)
+	}
+	if speed := uint32OrNil(data.Speed); speed != nil {
+		settings.Speed = *speed
+	}
+	return settings
+}
+
+// ethtoolToState returns data with the configured settings replaced by the
+// current ones, settings that aren't configured stay null
+func ethtoolToState(data *models.EthtoolModel, current *linuxhost_client.EthtoolSettings) *models.EthtoolModel {
+	state := *data
+	if data.Features != nil {
+		state.Features = map[string]types.Bool{}
+		for feature, value := range data.Features {
+			if v, ok := current.Features[linuxhost_client.EthtoolFeatureName(feature)]; ok && !value.IsNull() {
+				state.Features[feature] = types.BoolValue(v)
+			} else {
+				state.Features[feature] = types.BoolNull()
+			}
+		}
+	}
+	if data.Ring != nil {
+		ring := *data.Ring
+		state.Ring = &ring
+		ethtoolNumbersToState(ethtoolRingNumbers(state.Ring), current.Ring)
+	}
+	if data.Channels != nil {
+		channels := *data.Channels
+		state.Channels = &channels
+		ethtoolNumbersToState(ethtoolChannelsNumbers(state.Channels), current.Channels)
+	}
+	if data.Coalesce != nil {
+		coalesce := *data.Coalesce
+		state.Coalesce = &coalesce
+		ethtoolNumbersToState(ethtoolCoalesceNumbers(state.Coalesce), current.Coalesce)
+		if !coalesce.AdaptiveRx.IsNull() {
+			state.Coalesce.AdaptiveRx = types.BoolPointerValue(current.AdaptiveRx)
+		}
+		if !coalesce.AdaptiveTx.IsNull() {
+			state.Coalesce.AdaptiveTx = types.BoolPointerValue(current.AdaptiveTx)
+		}
+	}
+	if !data.Speed.IsNull() {
+		state.Speed = types.Int32Null()
+		if current.Speed != 0 {
+			state.Speed = int32OrNull(current.Speed)
+		}
+	}
+	if !data.Duplex.IsNull() {
+		state.Duplex = nonEmptyStringOrNull(current.Duplex)
+	}
+	if !data.Autoneg.IsNull() {
+		state.Autoneg = types.BoolPointerValue(current.Autoneg)
+	}
+	return &state
+}
+
+func (r *EthtoolResource) readState(ctx context.Context, data *models.EthtoolModel, State *tfsdk.State, Diagnostics *diag.Diagnostics) {
+	current, err := linuxhost_client.ReadEthtool(r.hostData.Client, data.Dev.ValueString())
+	if err != nil {
+		Diagnostics.AddError("Failed reading ethtool settings", err.Error())
+		return
+	}
+	if current == nil {
+		State.RemoveResource(ctx)
+		return
+	}
+	Diagnostics.Append(State.Set(ctx, ethtoolToState(data, current))...)
+}
+
+// apply sets the planned settings and checks they were taken by the device
+func (r *EthtoolResource) apply(ctx context.Context, data *models.EthtoolModel, State *tfsdk.State, Diagnostics *diag.Diagnostics) {
+	dev := data.Dev.ValueString()
+	desired := ethtoolFromModel(data)
+	if err := linuxhost_client.SetEthtool(r.hostData.Client, dev, desired); err != nil {
+		Diagnostics.AddError("Failed applying ethtool settings", err.Error())
+		return
+	}
+	current, err := linuxhost_client.ReadEthtool(r.hostData.Client, dev)
+	if err != nil {
+		Diagnostics.AddError("Failed reading ethtool settings", err.Error())
+		return
+	}
+	if current == nil {
+		Diagnostics.AddError("Interface not found", fmt.Sprintf("The interface %s disappeared while it was configured.", dev))
+		return
+	}
+	if drift := linuxhost_client.EthtoolDrift(desired, current); len(drift) > 0 {
+		Diagnostics.AddError("Settings not applied", fmt.Sprintf("ethtool reported no error but %s of %s didn't change.", strings.Join(drift, ", "), dev))
+		return
+	}
+	Diagnostics.Append(State.Set(ctx, data)...)
+}
+
+func (r *EthtoolResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.EthtoolModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &data, &resp.State, &resp.Diagnostics)
+}
+
+func (r *EthtoolResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.EthtoolModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics)
+}
+
+func (r *EthtoolResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.EthtoolModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, &resp.State, &resp.Diagnostics)
+}
+
+// Delete leaves the settings, the previous values aren't known
+func (r *EthtoolResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
@@ -18,6 +18,7 @@ import (
 )
 
 var _ resource.ResourceWithConfigure = &GroupResource{}
+var _ resource.ResourceWithImportState = &GroupResource{}
 
 func NewGroupResource() resource.Resource {
 	return &GroupResource{}
@@ -126,5 +127,5 @@ func (r *GroupResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	r.makeStateRefresher(ctx, &resp.State, &resp.Diagnostics).InState(data, "absent")
 }
 func (r *GroupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
@@ -0,0 +1,174 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &HostsEntryResource{}
+var _ resource.ResourceWithImportState = &HostsEntryResource{}
+
+func NewHostsEntryResource() resource.Resource {
+	return &HostsEntryResource{}
+}
+
+type HostsEntryResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *HostsEntryResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hosts_entry"
+}
+
+func (r *HostsEntryResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A line of /etc/hosts for names that aren't in DNS. The provider keeps its entries in a marked block and leaves the rest of the file alone, the file is replaced atomically.",
+		Attributes: map[string]schema.Attribute{
+			"ip": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The IPv4 or IPv6 address of the entry, each address has at most one entry",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9A-Fa-f.:]+$`), "must be an IPv4 or IPv6 address"),
+				},
+			},
+			"hostnames": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Required:            true,
+				MarkdownDescription: "The names of the address, the first one is its canonical name",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ValueStringsAre(
+						stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9._-]+$`), "must only contain letters, digits, '.', '_' and '-'"),
+					),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A comment written after the names",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^(\S([^\n]*\S)?)?$`), "must be a single line without leading or trailing spaces"),
+				},
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *HostsEntryResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *HostsEntryResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("ip"), req, resp)
+}
+
+func hostsEntryFromModel(ctx context.Context, data *models.HostsEntryModel, Diagnostics *diag.Diagnostics) *linuxhost_client.HostsEntry {
+	entry := &linuxhost_client.HostsEntry{
+		IP:      data.Ip.ValueString(),
+		Comment: data.Comment.ValueString(),
+	}
+	Diagnostics.Append(data.Hostnames.ElementsAs(ctx, &entry.Hostnames, false)...)
+	return entry
+}
+
+// readState finds the entry of the address in the managed block, entries
+// edited by hand are reported as they are
+func (r *HostsEntryResource) readState(ctx context.Context, data *models.HostsEntryModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
+	entry, err := linuxhost_client.ReadHostsEntry(r.hostData.Client, data.Ip.ValueString())
+	if err != nil {
+		Diagnostics.AddError("Failed reading /etc/hosts", err.Error())
+		return
+	}
+	if entry == nil {
+		if expect == "present" {
+			Diagnostics.AddError("Didn't find hosts entry", "")
+		} else {
+			State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	current := *data
+	hostnames, diags := types.ListValueFrom(ctx, types.StringType, entry.Hostnames)
+	Diagnostics.Append(diags...)
+	current.Hostnames = hostnames
+	if !data.Comment.IsNull() || entry.Comment != "" {
+		current.Comment = types.StringValue(entry.Comment)
+	}
+	Diagnostics.Append(State.Set(ctx, &current)...)
+}
+
+func (r *HostsEntryResource) apply(ctx context.Context, data *models.HostsEntryModel, State *tfsdk.State, Diagnostics *diag.Diagnostics) {
+	entry := hostsEntryFromModel(ctx, data, Diagnostics)
+	if Diagnostics.HasError() {
+		return
+	}
+	if err := linuxhost_client.WriteHostsEntry(r.hostData.Client, data.Ip.ValueString(), entry); err != nil {
+		Diagnostics.AddError("Failed writing /etc/hosts", err.Error())
+		return
+	}
+	r.readState(ctx, data, State, Diagnostics, "present")
+}
+
+func (r *HostsEntryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.HostsEntryModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &data, &resp.State, &resp.Diagnostics)
+}
+
+func (r *HostsEntryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.HostsEntryModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "any")
+}
+
+func (r *HostsEntryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.HostsEntryModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(ctx, &plan, &resp.State, &resp.Diagnostics)
+}
+
+func (r *HostsEntryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.HostsEntryModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.WriteHostsEntry(r.hostData.Client, data.Ip.ValueString(), nil); err != nil {
+		resp.Diagnostics.AddError("Failed to delete hosts entry", err.Error())
+	}
+}
@@ -5,14 +5,24 @@ import (
 	"terraform-provider-linuxhost/linuxhost_client"
 	models "terraform-provider-linuxhost/models"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.ResourceWithConfigure = &IfBridgeResource{}
+var _ resource.ResourceWithImportState = &IfBridgeResource{}
 
 func NewIfBridgeResource() resource.Resource {
 	return &IfBridgeResource{}
@@ -34,6 +44,33 @@ func (r *IfBridgeResource) Metadata(ctx context.Context, req resource.MetadataRe
 
 func (r *IfBridgeResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
 	attributes := commonInterfaceSchema()
+	attributes["vlan_filtering"] = schema.BoolAttribute{
+		MarkdownDescription: "Whether the bridge filters traffic by VLAN. Required for `linuxhost_bridge_vlan` port memberships to take effect.",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+	}
+	attributes["vlan_default_pvid"] = schema.Int32Attribute{
+		MarkdownDescription: "The VLAN ID assigned to untagged traffic on ports added to the bridge, 0 disables it.",
+		Optional:            true,
+		Computed:            true,
+		Default:             int32default.StaticInt32(1),
+		Validators: []validator.Int32{
+			int32validator.Between(0, 4094),
+		},
+	}
+	attributes["vlan_protocol"] = schema.StringAttribute{
+		MarkdownDescription: "The VLAN protocol used for filtering. Valid options: '802.1Q', '802.1ad'.",
+		Optional:            true,
+		Computed:            true,
+		Default:             stringdefault.StaticString("802.1Q"),
+		Validators: []validator.String{
+			stringvalidator.OneOf("802.1Q", "802.1ad"),
+		},
+	}
+	for name, attribute := range bridgeTuningSchema() {
+		attributes[name] = attribute
+	}
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "A bridge interface",
 		Version:             1,
@@ -41,6 +78,41 @@ func (r *IfBridgeResource) Schema(ctx context.Context, req resource.SchemaReques
 	}
 }
 
+// bridgeTuningSchema holds the optional STP, timer and multicast settings.
+// Unset values are read back from the host.
+func bridgeTuningSchema() map[string]schema.Attribute {
+	optionalInt32 := func(description string, min int32, max int32) schema.Int32Attribute {
+		return schema.Int32Attribute{
+			MarkdownDescription: description,
+			Optional:            true,
+			Computed:            true,
+			PlanModifiers:       []planmodifier.Int32{int32planmodifier.UseStateForUnknown()},
+			Validators:          []validator.Int32{int32validator.Between(min, max)},
+		}
+	}
+	optionalBool := func(description string) schema.BoolAttribute {
+		return schema.BoolAttribute{
+			MarkdownDescription: description,
+			Optional:            true,
+			Computed:            true,
+			PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+		}
+	}
+	return map[string]schema.Attribute{
+		"stp":                optionalBool("Whether the spanning tree protocol is enabled"),
+		"forward_delay":      optionalInt32("The STP forward delay in centiseconds", 200, 3000),
+		"hello_time":         optionalInt32("The STP hello time in centiseconds", 100, 1000),
+		"max_age":            optionalInt32("The STP max message age in centiseconds", 600, 4000),
+		"ageing_time":        optionalInt32("The time in centiseconds a learned MAC address is kept in the forwarding database", 0, 100000000),
+		"priority":           optionalInt32("The STP bridge priority, lower is preferred as root", 0, 65535),
+		"group_fwd_mask":     optionalInt32("Bitmask of link local group addresses (01:80:C2:00:00:0X) to forward", 0, 65535),
+		"mcast_snooping":     optionalBool("Whether IGMP/MLD snooping is enabled"),
+		"mcast_querier":      optionalBool("Whether the bridge sends IGMP/MLD queries itself"),
+		"mcast_igmp_version": optionalInt32("The IGMP version used by the querier", 2, 3),
+		"mcast_mld_version":  optionalInt32("The MLD version used by the querier", 1, 2),
+	}
+}
+
 func (r *IfBridgeResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
 	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
 }
@@ -55,7 +127,22 @@ func convertIfBridgeResourceModel(ctx context.Context, getter Getter) (*models.I
 	tflog.Debug(ctx, "did base conversion successfully")
 
 	internal := &linuxhost_client.IfBridge{
-		IfCommon: *internalBase,
+		IfCommon:        *internalBase,
+		VlanFiltering:   resource.VlanFiltering.ValueBool(),
+		VlanDefaultPvid: uint32OrNil(resource.VlanDefaultPvid),
+		VlanProtocol:    resource.VlanProtocol.ValueString(),
+
+		Stp:              boolOrNil(resource.Stp),
+		ForwardDelay:     uint32OrNil(resource.ForwardDelay),
+		HelloTime:        uint32OrNil(resource.HelloTime),
+		MaxAge:           uint32OrNil(resource.MaxAge),
+		AgeingTime:       uint32OrNil(resource.AgeingTime),
+		Priority:         uint32OrNil(resource.Priority),
+		GroupFwdMask:     uint32OrNil(resource.GroupFwdMask),
+		McastSnooping:    boolOrNil(resource.McastSnooping),
+		McastQuerier:     boolOrNil(resource.McastQuerier),
+		McastIgmpVersion: uint32OrNil(resource.McastIgmpVersion),
+		McastMldVersion:  uint32OrNil(resource.McastMldVersion),
 	}
 	return resource, internal, diags
 }
@@ -63,6 +150,21 @@ func convertIfBridgeResourceModel(ctx context.Context, getter Getter) (*models.I
 func convertBridgeIf(m *models.IfCommonResourceModel, a *linuxhost_client.AdapterInfo, all *linuxhost_client.AdapterInfoSlice) *models.IfBridgeResourceModel {
 	rm := &models.IfBridgeResourceModel{
 		IfCommonResourceModel: *m,
+		VlanFiltering:         types.BoolValue(a.BridgeInfo.VlanFiltering),
+		VlanDefaultPvid:       int32OrNull(a.BridgeInfo.VlanDefaultPvid),
+		VlanProtocol:          stringOrNull(a.BridgeInfo.VlanProtocol),
+
+		Stp:              types.BoolValue(a.BridgeInfo.Stp),
+		ForwardDelay:     int32OrNull(a.BridgeInfo.ForwardDelay),
+		HelloTime:        int32OrNull(a.BridgeInfo.HelloTime),
+		MaxAge:           int32OrNull(a.BridgeInfo.MaxAge),
+		AgeingTime:       int32OrNull(a.BridgeInfo.AgeingTime),
+		Priority:         int32OrNull(a.BridgeInfo.Priority),
+		GroupFwdMask:     int32OrNull(a.BridgeInfo.GroupFwdMask),
+		McastSnooping:    types.BoolValue(a.BridgeInfo.McastSnooping),
+		McastQuerier:     types.BoolValue(a.BridgeInfo.McastQuerier),
+		McastIgmpVersion: int32OrNull(a.BridgeInfo.McastIgmpVersion),
+		McastMldVersion:  int32OrNull(a.BridgeInfo.McastMldVersion),
 	}
 	// Get to see if bridge has members
 	members := all.SelectWithDesignatedBridge(&a.BridgeInfo.BridgeId)
@@ -86,16 +188,21 @@ func (r *IfBridgeResource) Create(ctx context.Context, req resource.CreateReques
 		return
 	}
 
-	_, err := linuxhost_client.CreateIfBridge(r.hostData.Client, internal)
+	err := createIf(r.hostData, internal, func() error {
+		_, err := linuxhost_client.CreateIfBridge(r.hostData.Client, internal)
+		return err
+	})
 
 	if err != nil {
 		resp.Diagnostics.AddError("Failed creating Bridge", err.Error())
 		return
 	}
+	r.hostData.Interfaces.Clear()
 
 	resp.Diagnostics.Append(IfToState(
 		r.hostData, resourceModel, ctx, &resp.State,
 		convertBridgeIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfBridgeResourceModel, true)...)
 }
 
 func (r *IfBridgeResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -109,6 +216,7 @@ func (r *IfBridgeResource) Read(ctx context.Context, req resource.ReadRequest, r
 	resp.Diagnostics.Append(IfToState(
 		r.hostData, resourceModel, ctx, &resp.State,
 		convertBridgeIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, nil, req.Private, convertIfBridgeResourceModel, false)...)
 }
 
 func (r *IfBridgeResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -120,18 +228,36 @@ func (r *IfBridgeResource) Update(ctx context.Context, req resource.UpdateReques
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	UpdateIf(r.hostData, desired, state, ctx, &resp.State)
+	if !desired.OptionsEqual(state) {
+		if err := linuxhost_client.IfSetBridgeOptions(r.hostData.Client, desired); err != nil {
+			resp.Diagnostics.AddError("Failed updating Bridge", err.Error())
+			return
+		}
+	}
+	resp.Diagnostics.Append(UpdateIf(r.hostData, desired, state, ctx, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, desiredM)...)
+	r.hostData.Interfaces.Clear()
 
+	resp.Diagnostics.Append(IfToState(
+		r.hostData, desiredM, ctx, &resp.State,
+		convertBridgeIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfBridgeResourceModel, true)...)
 }
 func (r *IfBridgeResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data models.IfBridgeResourceModel
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
-	resourceModel, _, diags := convertIfBridgeResourceModel(ctx, &req.State)
+	resourceModel, internal, diags := convertIfBridgeResourceModel(ctx, &req.State)
 	resp.Diagnostics.Append(*diags...)
-	linuxhost_client.DeleteInterface(r.hostData.Client, resourceModel.Name.ValueString())
+	resp.Diagnostics.Append(IfRemovePersistence(r.hostData, internal)...)
+	linuxhost_client.DeleteInterfaceInNetns(r.hostData.Client, resourceModel.Netns.ValueString(), resourceModel.Name.ValueString())
+
+}
 
+func (r *IfBridgeResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ImportIf(ctx, r.hostData, "bridge", req, resp)
 }
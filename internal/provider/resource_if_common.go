@@ -2,16 +2,26 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
 	"terraform-provider-linuxhost/linuxhost_client"
 	models "terraform-provider-linuxhost/models"
 
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -26,11 +36,39 @@ func commonInterfaceSchema() map[string]schema.Attribute {
 			},
 		},
 		"mac": schema.StringAttribute{
+			Optional:            true,
 			Computed:            true,
-			MarkdownDescription: "The assigned interface mac address",
+			MarkdownDescription: "The interface mac address in lower case. If unspecified the assigned address is kept.",
 			PlanModifiers: []planmodifier.String{
 				stringplanmodifier.UseStateForUnknown(),
 			},
+			Validators: []validator.String{
+				stringvalidator.RegexMatches(regexp.MustCompile(`^([0-9a-f]{2}:){5}[0-9a-f]{2}$`), "must be a lower case mac address, e.g. '52:54:00:12:34:56'"),
+			},
+		},
+		"mtu": schema.Int32Attribute{
+			Optional:            true,
+			Computed:            true,
+			MarkdownDescription: "The MTU of the interface, e.g. 1450 for VXLAN overlays",
+			PlanModifiers:       []planmodifier.Int32{int32planmodifier.UseStateForUnknown()},
+			Validators:          []validator.Int32{int32validator.Between(68, 65535)},
+		},
+		"txqueuelen": schema.Int32Attribute{
+			Optional:            true,
+			Computed:            true,
+			MarkdownDescription: "The length of the transmit queue",
+			PlanModifiers:       []planmodifier.Int32{int32planmodifier.UseStateForUnknown()},
+			Validators:          []validator.Int32{int32validator.AtLeast(1)},
+		},
+		"alias": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "A description of the interface",
+		},
+		"group": schema.StringAttribute{
+			Optional:            true,
+			Computed:            true,
+			MarkdownDescription: "The interface group, by name from /etc/iproute2/group or by number",
+			PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
 		},
 		"ipv4": schema.SetAttribute{
 			ElementType:   types.StringType,
@@ -45,6 +83,14 @@ func commonInterfaceSchema() map[string]schema.Attribute {
 				stringvalidator.OneOf("up", "down"),
 			},
 		},
+		"netns": schema.StringAttribute{
+			Optional:            true,
+			MarkdownDescription: "If specified, the network namespace the interface lives in, either a name as created by `linuxhost_netns` or the PID of a process inside the namespace.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.RequiresReplace(),
+			},
+		},
+		"persistence": persistenceSchema("interface"),
 		"bridge": schema.SingleNestedAttribute{
 			Optional: true,
 			MarkdownDescription: "If specified, the bridge this interface is a member of.",
@@ -53,11 +99,53 @@ func commonInterfaceSchema() map[string]schema.Attribute {
 					MarkdownDescription: "The name of the bridge, e.g. 'br0'",
 					Required: true,
 				},
+				"cost":           bridgePortInt32("The STP path cost of the port", 1, 65535),
+				"priority":       bridgePortInt32("The STP priority of the port", 0, 63),
+				"hairpin":        bridgePortBool("Whether traffic may be sent back out of the port it was received on"),
+				"learning":       bridgePortBool("Whether source MAC addresses are learned on the port"),
+				"flood":          bridgePortBool("Whether unknown unicast traffic is flooded to the port"),
+				"mcast_flood":    bridgePortBool("Whether unknown multicast traffic is flooded to the port"),
+				"neigh_suppress": bridgePortBool("Whether ARP and ND suppression is enabled on the port"),
+				"isolated":       bridgePortBool("Whether the port is isolated, i.e. can only talk to non-isolated ports"),
+				"bpdu_guard":     bridgePortBool("Whether STP BPDUs received on the port are blocked"),
 			},
 		},
 	}
 }
 
+// bridgePortInt32 and bridgePortBool describe the optional bridge port
+// settings. Unset values are read back from the host.
+func bridgePortInt32(description string, min int32, max int32) schema.Int32Attribute {
+	return schema.Int32Attribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers:       []planmodifier.Int32{int32planmodifier.UseStateForUnknown()},
+		Validators:          []validator.Int32{int32validator.Between(min, max)},
+	}
+}
+
+func bridgePortBool(description string) schema.BoolAttribute {
+	return schema.BoolAttribute{
+		MarkdownDescription: description,
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers:       []planmodifier.Bool{boolplanmodifier.UseStateForUnknown()},
+	}
+}
+
+// persistenceSchema is the backend saving the configuration of an interface
+// or address so it survives a reboot
+func persistenceSchema(what string) schema.StringAttribute {
+	return schema.StringAttribute{
+		Optional:            true,
+		MarkdownDescription: "If specified, the " + what + " is also written to the configuration of a network manager so it survives a reboot. Valid options: `networkd`, which writes files to /etc/systemd/network without reloading systemd-networkd, `netplan`, which adds it to /etc/netplan/90-terraform-linuxhost.yaml and validates it with `netplan generate`, `nmcli`, which writes a NetworkManager connection profile, and `ifupdown`, which writes a stanza file to /etc/network/interfaces.d for ifupdown2. With the provider's `network_backend = \"nmcli\"` interfaces are NetworkManager profiles already. Configuration that no longer matches is rewritten.",
+		Validators: []validator.String{
+			stringvalidator.OneOf("networkd", "netplan", "nmcli", "ifupdown"),
+		},
+	}
+}
+
 // func GenericCreate[Model InterfaceConfig](
 //
 //	ctx context.Context,
@@ -99,9 +187,26 @@ func BuildIfInternalCommon(resource models.IsIfResourceModel, diags *diag.Diagno
 		internal.State = common.State.ValueString()
 	}
 
+	internal.Netns = common.Netns.ValueString()
+	internal.Persistence = common.Persistence.ValueString()
+	internal.Mac = common.Mac.ValueString()
+	internal.Mtu = uint32(common.Mtu.ValueInt32())
+	internal.TxQueueLen = uint32(common.TxQueueLen.ValueInt32())
+	internal.Alias = common.Alias.ValueString()
+	internal.Group = common.Group.ValueString()
+
 	if common.Bridge != nil {
 		internal.BridgeMember = &linuxhost_client.IfBridgeMember{
-			Name: common.Bridge.Name.ValueString(),
+			Name:          common.Bridge.Name.ValueString(),
+			Cost:          uint32OrNil(common.Bridge.Cost),
+			Priority:      uint32OrNil(common.Bridge.Priority),
+			Hairpin:       boolOrNil(common.Bridge.Hairpin),
+			Learning:      boolOrNil(common.Bridge.Learning),
+			Flood:         boolOrNil(common.Bridge.Flood),
+			McastFlood:    boolOrNil(common.Bridge.McastFlood),
+			NeighSuppress: boolOrNil(common.Bridge.NeighSuppress),
+			Isolated:      boolOrNil(common.Bridge.Isolated),
+			BpduGuard:     boolOrNil(common.Bridge.BpduGuard),
 		}
 	}
 	return internal
@@ -154,6 +259,17 @@ func BuildIfResourceModelFromInternal(
 		Mac:   types.StringValue(interfaceDescription.MAC),
 		State: types.StringValue(interfaceState),
 		IP4s:  interfaceIPv4s,
+
+		Mtu:        types.Int32Null(),
+		TxQueueLen: types.Int32Null(),
+		Alias:      nonEmptyStringOrNull(interfaceDescription.Alias),
+		Group:      nonEmptyStringOrNull(interfaceDescription.Group),
+	}
+	if interfaceDescription.Mtu != 0 {
+		commonResourceModel.Mtu = int32OrNull(interfaceDescription.Mtu)
+	}
+	if interfaceDescription.TxQueueLen != 0 {
+		commonResourceModel.TxQueueLen = int32OrNull(interfaceDescription.TxQueueLen)
 	}
 	if interfaceDescription.DesignatedBridge != nil {
 		// Find the bridge
@@ -166,6 +282,17 @@ func BuildIfResourceModelFromInternal(
 		commonResourceModel.Bridge = &models.IfBridgeMemberResourceModel{
 			Name: types.StringValue(bridge.Name),
 		}
+		if port := interfaceDescription.BridgePortInfo; port != nil {
+			commonResourceModel.Bridge.Cost = int32OrNull(port.Cost)
+			commonResourceModel.Bridge.Priority = int32OrNull(port.Priority)
+			commonResourceModel.Bridge.Hairpin = types.BoolValue(port.Hairpin)
+			commonResourceModel.Bridge.Learning = types.BoolValue(port.Learning)
+			commonResourceModel.Bridge.Flood = types.BoolValue(port.Flood)
+			commonResourceModel.Bridge.McastFlood = types.BoolValue(port.McastFlood)
+			commonResourceModel.Bridge.NeighSuppress = types.BoolValue(port.NeighSuppress)
+			commonResourceModel.Bridge.Isolated = types.BoolValue(port.Isolated)
+			commonResourceModel.Bridge.BpduGuard = types.BoolValue(port.BpduGuard)
+		}
 	}
 	return commonResourceModel, diags
 }
@@ -177,7 +304,8 @@ func IfToState[RM models.IsIfResourceModel](
 	setter Setter,
 	provideFinalState func(m *models.IfCommonResourceModel, a *linuxhost_client.AdapterInfo, all *linuxhost_client.AdapterInfoSlice) RM,
 ) diag.Diagnostics {
-	adapters, _ := linuxhost_client.ReadAdapters(hostData)
+	netns := resourceModel.GetCommon().Netns
+	adapters, _ := linuxhost_client.ReadAdaptersInNetns(hostData, netns.ValueString())
 	interfaceDescription := adapters.GetByName(resourceModel.GetCommon().Name.ValueString())
 	commonResourceModel, diags := BuildIfResourceModelFromInternal(ctx, &adapters, interfaceDescription)
 
@@ -190,6 +318,8 @@ func IfToState[RM models.IsIfResourceModel](
 		return diags
 	}
 
+	commonResourceModel.Netns = netns
+	commonResourceModel.Persistence = resourceModel.GetCommon().Persistence
 	finalModel := provideFinalState(commonResourceModel, interfaceDescription, &adapters)
 	return setter.Set(ctx, finalModel)
 
@@ -201,14 +331,233 @@ func UpdateIf[M linuxhost_client.IsIf](
 	modelState M,
 	ctx context.Context,
 	setter Setter,
-) {
+) diag.Diagnostics {
+	diags := diag.Diagnostics{}
 	desired := modelDesired.GetCommon()
 	state := modelState.GetCommon()
+	if previous := ifPersistenceName(hostData, modelState); previous != ifPersistenceName(hostData, modelDesired) && !nmcliManaged(hostData, modelState) {
+		tflog.Info(ctx, "Persistence changed from "+previous)
+		diags.Append(IfRemovePersistence(hostData, modelState)...)
+	}
+	if err := linuxhost_client.IfUpdateLink(hostData.Client, modelDesired, modelState); err != nil {
+		diags.AddError("Failed updating interface "+desired.Name, (*err).Error())
+		return diags
+	}
 	if state.State != desired.State {
-		linuxhost_client.IfSetState(hostData.Client, modelDesired)
+		if err := linuxhost_client.IfSetState(hostData.Client, modelDesired); err != nil {
+			diags.AddError("Failed setting state of interface "+desired.Name, (*err).Error())
+			return diags
+		}
 	}
-	if &state.BridgeMember != &desired.BridgeMember {
+	if bridgeName(state.BridgeMember) != bridgeName(desired.BridgeMember) {
 		tflog.Info(ctx, "Bridge member changed")
-		linuxhost_client.IfSetBridgeMaster(hostData.Client, modelDesired)
+		if err := linuxhost_client.IfSetBridgeMaster(hostData.Client, modelDesired); err != nil {
+			diags.AddError("Failed setting bridge of interface "+desired.Name, (*err).Error())
+			return diags
+		}
+	}
+	if err := linuxhost_client.IfSetBridgePort(hostData.Client, modelDesired); err != nil {
+		diags.AddError("Failed setting bridge port of interface "+desired.Name, (*err).Error())
+	}
+	return diags
+}
+
+// bridgeName is the bridge of a member, empty if it isn't one
+func bridgeName(member *linuxhost_client.IfBridgeMember) string {
+	if member == nil {
+		return ""
+	}
+	return member.Name
+}
+
+// IfPersist saves the interface in state to its persistence backend. With
+// save false it only verifies the saved files and changes persistence in
+// state if they don't match, so the next apply writes them again. config is
+// nil when verifying.
+func IfPersist[RM models.IsIfResourceModel, M linuxhost_client.IsIf](
+	hostData *linuxhost_client.HostData,
+	ctx context.Context,
+	state *tfsdk.State,
+	config *tfsdk.Config,
+	private privateState,
+	convert func(context.Context, Getter) (RM, M, *diag.Diagnostics),
+	save bool,
+) diag.Diagnostics {
+	if state.Raw.IsNull() {
+		return diag.Diagnostics{}
+	}
+	_, internal, diags := convert(ctx, state)
+	if diags.HasError() {
+		return *diags
+	}
+	return persistIf(hostData, ctx, internal, state, config, private, path.Root("persistence"), save)
+}
+
+func persistIf(
+	hostData *linuxhost_client.HostData,
+	ctx context.Context,
+	iface linuxhost_client.IsIf,
+	state *tfsdk.State,
+	config *tfsdk.Config,
+	private privateState,
+	attribute path.Path,
+	save bool,
+) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	name := iface.GetCommon().Name
+	backend, err := linuxhost_client.GetPersistence(hostData, ifPersistenceName(hostData, iface))
+	if err != nil {
+		diags.AddError("Invalid persistence for "+name, err.Error())
+		return diags
+	}
+	if backend == nil {
+		return diags
+	}
+	// The mac in state is the assigned one unless it's configured, only a
+	// configured one is persisted. A bridge takes the mac of its ports.
+	configured, macDiags := ifMacConfigured(ctx, config, private, attribute.ParentPath().AtName("mac"))
+	diags.Append(macDiags...)
+	if !configured {
+		iface.GetCommon().Mac = ""
+	}
+	if save {
+		if err := backend.SaveIf(hostData.Client, iface); err != nil {
+			diags.AddError("Failed persisting interface "+name, err.Error())
+		}
+		return diags
+	}
+	if nmcliManaged(hostData, iface) {
+		// Without its profile NetworkManager no longer manages the interface
+		exists, err := linuxhost_client.NmcliConnectionExists(hostData.Client, linuxhost_client.NmcliConnectionName(name))
+		if err != nil {
+			diags.AddError("Failed reading NetworkManager profile of "+name, err.Error())
+			return diags
+		}
+		if !exists {
+			tflog.Info(ctx, "NetworkManager profile of "+name+" is gone")
+			state.RemoveResource(ctx)
+			return diags
+		}
+	}
+	differs, err := backend.IfDiff(hostData.Client, iface)
+	if err != nil {
+		diags.AddError("Failed verifying persisted configuration of "+name, err.Error())
+		return diags
+	}
+	if differs != "" {
+		tflog.Info(ctx, "Persisted configuration of "+name+" is outdated")
+		diags.AddAttributeWarning(attribute, "Persisted configuration of "+name+" differs", differs+" no longer matches the interface, it is rewritten on the next apply.")
+		if iface.GetCommon().Persistence == "" {
+			// The profile of the nmcli network backend isn't configured, state
+			// claims it is so the plan updates the interface and rewrites it
+			diags.Append(state.SetAttribute(ctx, attribute, types.StringValue("nmcli"))...)
+		} else {
+			diags.Append(state.SetAttribute(ctx, attribute, types.StringNull())...)
+		}
+	}
+	return diags
+}
+
+// ifMacConfigured tells if the mac at attribute is configured. Read has no
+// config, so it's recorded in private state when the interface is applied.
+func ifMacConfigured(ctx context.Context, config *tfsdk.Config, private privateState, attribute path.Path) (bool, diag.Diagnostics) {
+	key := "mac_configured:" + attribute.String()
+	if config == nil {
+		value, diags := private.GetKey(ctx, key)
+		return string(value) == "true", diags
+	}
+	var mac types.String
+	diags := config.GetAttribute(ctx, attribute, &mac)
+	configured := !mac.IsNull()
+	diags.Append(private.SetKey(ctx, key, []byte(strconv.FormatBool(configured)))...)
+	return configured, diags
+}
+
+// ifPersistenceName is the persistence backend of an interface. With the
+// nmcli network backend the profile of an interface always persists it.
+func ifPersistenceName(hostData *linuxhost_client.HostData, iface linuxhost_client.IsIf) string {
+	name := iface.GetCommon().Persistence
+	if name == "" && nmcliManaged(hostData, iface) {
+		return "nmcli"
+	}
+	return name
+}
+
+// nmcliManaged tells if the interface is a NetworkManager profile, which is
+// the case for the supported kinds with the nmcli network backend
+func nmcliManaged(hostData *linuxhost_client.HostData, iface linuxhost_client.IsIf) bool {
+	return hostData.NetworkBackend == "nmcli" && linuxhost_client.NmcliSupports(iface)
+}
+
+// createIf creates an interface with create, or as a NetworkManager profile
+// with the nmcli network backend
+func createIf(hostData *linuxhost_client.HostData, iface linuxhost_client.IsIf, create func() error) error {
+	if nmcliManaged(hostData, iface) {
+		return linuxhost_client.NmcliCreateIf(hostData.Client, iface)
+	}
+	return create()
+}
+
+// IfRemovePersistence removes the saved configuration of an interface
+func IfRemovePersistence(hostData *linuxhost_client.HostData, iface linuxhost_client.IsIf) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	name := iface.GetCommon().Name
+	backend, err := linuxhost_client.GetPersistence(hostData, ifPersistenceName(hostData, iface))
+	if err != nil || backend == nil {
+		return diags
+	}
+	if err := backend.RemoveIf(hostData.Client, iface); err != nil {
+		diags.AddWarning("Failed removing persisted configuration of "+name, err.Error())
+	}
+	return diags
+}
+
+// splitIfImportId splits an import id "<name>" or "<netns>/<name>"
+func splitIfImportId(id string) (string, string) {
+	if netns, name, found := strings.Cut(id, "/"); found {
+		return netns, name
+	}
+	return "", id
+}
+
+// importedAdapter returns the interface to import after checking it has the
+// type kind
+func importedAdapter(hostData *linuxhost_client.HostData, netns string, name string, kind string) (*linuxhost_client.AdapterInfo, diag.Diagnostics) {
+	diags := diag.Diagnostics{}
+	var adapters linuxhost_client.AdapterInfoSlice
+	var err error
+	if netns == "" {
+		adapters, err = linuxhost_client.RefreshAdapters(hostData)
+	} else {
+		adapters, err = linuxhost_client.ReadAdaptersInNetns(hostData, netns)
+	}
+	if err != nil {
+		diags.AddError("Failed to read interfaces", err.Error())
+		return nil, diags
+	}
+	adapter := adapters.GetByName(name)
+	if adapter == nil {
+		diags.AddError("Interface not found", fmt.Sprintf("The interface %s does not exist.", name))
+		return nil, diags
+	}
+	if adapter.Type != kind {
+		diags.AddError("Unexpected interface type", fmt.Sprintf("The interface %s is of type %s, not %s.", name, adapter.Type, kind))
+		return nil, diags
+	}
+	return adapter, diags
+}
+
+// ImportIf imports the interface by name, "<netns>/<name>" for one in a
+// namespace. Read populates the rest of the state.
+func ImportIf(ctx context.Context, hostData *linuxhost_client.HostData, kind string, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	netns, name := splitIfImportId(req.ID)
+	_, diags := importedAdapter(hostData, netns, name, kind)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("name"), name)...)
+	if netns != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("netns"), netns)...)
 	}
 }
@@ -0,0 +1,143 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.ResourceWithConfigure = &IfDummyResource{}
+var _ resource.ResourceWithImportState = &IfDummyResource{}
+var _ IsLinuxhostIFResource = &IfDummyResource{}
+
+func NewIfDummyResource() resource.Resource {
+	return &IfDummyResource{}
+}
+
+type IfDummyResource struct {
+	LinuxhostCommonResource
+}
+
+func (r *IfDummyResource) GetHostData() *linuxhost_client.HostData {
+	return r.hostData
+}
+
+func (r *IfDummyResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_if_dummy"
+}
+
+func (r *IfDummyResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := commonInterfaceSchema()
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A dummy interface, e.g. for loopback-style service addresses",
+		Version:             1,
+		Attributes:          attributes,
+	}
+}
+
+func (r *IfDummyResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func convertIfDummyResourceModel(ctx context.Context, getter Getter) (*models.IfDummyResourceModel, *linuxhost_client.IfDummy, *diag.Diagnostics) {
+	tflog.Debug(ctx, "converting IfDummyResourceModel")
+	resource, internalBase, diags := ExtractIfResourceModel[*models.IfDummyResourceModel](ctx, getter)
+	if diags.HasError() {
+		tflog.Debug(ctx, "Error converting IfDummyResourceModel")
+		return nil, nil, diags
+	}
+	tflog.Debug(ctx, "did base conversion successfully")
+
+	internal := &linuxhost_client.IfDummy{
+		IfCommon: *internalBase,
+	}
+	return resource, internal, diags
+}
+
+func convertDummyIf(m *models.IfCommonResourceModel, a *linuxhost_client.AdapterInfo, all *linuxhost_client.AdapterInfoSlice) *models.IfDummyResourceModel {
+	rm := &models.IfDummyResourceModel{
+		IfCommonResourceModel: *m,
+	}
+	return rm
+}
+
+func (r *IfDummyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resourceModel, internal, diags := convertIfDummyResourceModel(ctx, &req.Plan)
+	resp.Diagnostics.Append(*diags...)
+
+	if diags.HasError() {
+		tflog.Error(ctx, "Exiting due to error")
+		return
+	}
+
+	err := createIf(r.hostData, internal, func() error {
+		_, err := linuxhost_client.CreateIfDummy(r.hostData.Client, internal)
+		return err
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed creating dummy", err.Error())
+		return
+	}
+	r.hostData.Interfaces.Clear()
+
+	resp.Diagnostics.Append(IfToState(
+		r.hostData, resourceModel, ctx, &resp.State,
+		convertDummyIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfDummyResourceModel, true)...)
+}
+
+func (r *IfDummyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resourceModel, _, diags := convertIfDummyResourceModel(ctx, &req.State)
+
+	resp.Diagnostics.Append(*diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(IfToState(
+		r.hostData, resourceModel, ctx, &resp.State,
+		convertDummyIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, nil, req.Private, convertIfDummyResourceModel, false)...)
+}
+
+func (r *IfDummyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	desiredM, desired, diagsA := convertIfDummyResourceModel(ctx, &req.Plan)
+	resp.Diagnostics.Append(*diagsA...)
+	_, state, diagsB := convertIfDummyResourceModel(ctx, &req.State)
+
+	resp.Diagnostics.Append(*diagsB...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(UpdateIf(r.hostData, desired, state, ctx, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.hostData.Interfaces.Clear()
+
+	resp.Diagnostics.Append(IfToState(
+		r.hostData, desiredM, ctx, &resp.State,
+		convertDummyIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfDummyResourceModel, true)...)
+}
+
+func (r *IfDummyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resourceModel, internal, diags := convertIfDummyResourceModel(ctx, &req.State)
+	resp.Diagnostics.Append(*diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(IfRemovePersistence(r.hostData, internal)...)
+	linuxhost_client.DeleteInterfaceInNetns(r.hostData.Client, resourceModel.Netns.ValueString(), resourceModel.Name.ValueString())
+}
+
+func (r *IfDummyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ImportIf(ctx, r.hostData, "dummy", req, resp)
+}
@@ -0,0 +1,270 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.ResourceWithConfigure = &IfTunnelResource{}
+var _ resource.ResourceWithImportState = &IfTunnelResource{}
+var _ resource.ResourceWithValidateConfig = &IfTunnelResource{}
+var _ IsLinuxhostIFResource = &IfTunnelResource{}
+
+var tunnelDescriptions = map[string]string{
+	"gre":    "A GRE tunnel interface",
+	"gretap": "A GRETAP tunnel interface, i.e. ethernet over GRE",
+	"ipip":   "An IPIP tunnel interface",
+	"sit":    "A SIT tunnel interface, i.e. IPv6 over IPv4",
+}
+
+func NewIfGreResource() resource.Resource {
+	return &IfTunnelResource{kind: "gre"}
+}
+func NewIfGretapResource() resource.Resource {
+	return &IfTunnelResource{kind: "gretap"}
+}
+func NewIfIpipResource() resource.Resource {
+	return &IfTunnelResource{kind: "ipip"}
+}
+func NewIfSitResource() resource.Resource {
+	return &IfTunnelResource{kind: "sit"}
+}
+
+// IfTunnelResource implements the gre, gretap, ipip and sit resources, which
+// only differ in the tunnel kind.
+type IfTunnelResource struct {
+	LinuxhostCommonResource
+	kind string
+}
+
+func (r *IfTunnelResource) GetHostData() *linuxhost_client.HostData {
+	return r.hostData
+}
+
+func (r *IfTunnelResource) supportsKey() bool {
+	return r.kind == "gre" || r.kind == "gretap"
+}
+
+func (r *IfTunnelResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_if_" + r.kind
+}
+
+func (r *IfTunnelResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := commonInterfaceSchema()
+	attributes["local"] = schema.StringAttribute{
+		MarkdownDescription: "The local address of the tunnel. If unspecified any local address is used.",
+		Optional:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+	attributes["remote"] = schema.StringAttribute{
+		MarkdownDescription: "The remote address of the tunnel",
+		Required:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+	}
+	attributes["ttl"] = schema.Int32Attribute{
+		MarkdownDescription: "The ttl of tunnelled packets. If unspecified the ttl is inherited from the inner packet.",
+		Optional:            true,
+		PlanModifiers: []planmodifier.Int32{
+			int32planmodifier.RequiresReplace(),
+		},
+		Validators: []validator.Int32{
+			int32validator.Between(1, 255),
+		},
+	}
+	attributes["key"] = schema.Int64Attribute{
+		MarkdownDescription: "The GRE key used in both directions. Only supported by gre and gretap.",
+		Optional:            true,
+		PlanModifiers: []planmodifier.Int64{
+			int64planmodifier.RequiresReplace(),
+		},
+		Validators: []validator.Int64{
+			int64validator.Between(0, 4294967295),
+		},
+	}
+	attributes["pmtudisc"] = schema.BoolAttribute{
+		MarkdownDescription: "Whether path MTU discovery is enabled on the tunnel. Disabling it requires an inherited ttl.",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(true),
+		PlanModifiers: []planmodifier.Bool{
+			boolplanmodifier.RequiresReplace(),
+		},
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: tunnelDescriptions[r.kind],
+		Version:             1,
+		Attributes:          attributes,
+	}
+}
+
+func (r *IfTunnelResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *IfTunnelResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config models.IfTunnelResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.supportsKey() && !config.Key.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("key"),
+			"Unexpected key",
+			"The 'key' attribute is only supported by gre and gretap tunnels.",
+		)
+	}
+	if !config.PmtuDisc.IsNull() && !config.PmtuDisc.IsUnknown() && !config.PmtuDisc.ValueBool() && !config.Ttl.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("ttl"),
+			"Unexpected ttl",
+			"When 'pmtudisc' is false, the 'ttl' attribute must not be provided.",
+		)
+	}
+}
+
+func (r *IfTunnelResource) convertIfTunnelResourceModel(ctx context.Context, getter Getter) (*models.IfTunnelResourceModel, *linuxhost_client.IfTunnel, *diag.Diagnostics) {
+	tflog.Debug(ctx, "converting IfTunnelResourceModel")
+	resource, internalBase, diags := ExtractIfResourceModel[*models.IfTunnelResourceModel](ctx, getter)
+	if diags.HasError() {
+		tflog.Debug(ctx, "Error converting IfTunnelResourceModel")
+		return nil, nil, diags
+	}
+	tflog.Debug(ctx, "did base conversion successfully")
+
+	internal := &linuxhost_client.IfTunnel{
+		IfCommon: *internalBase,
+		Kind:     r.kind,
+		Local:    resource.Local.ValueString(),
+		Remote:   resource.Remote.ValueString(),
+		Ttl:      uint32(resource.Ttl.ValueInt32()),
+		PmtuDisc: resource.PmtuDisc.ValueBool(),
+	}
+	if !resource.Key.IsNull() && !resource.Key.IsUnknown() {
+		key := uint32(resource.Key.ValueInt64())
+		internal.Key = &key
+	}
+	return resource, internal, diags
+}
+
+func convertTunnelIf(m *models.IfCommonResourceModel, a *linuxhost_client.AdapterInfo, all *linuxhost_client.AdapterInfoSlice) *models.IfTunnelResourceModel {
+	rm := &models.IfTunnelResourceModel{
+		IfCommonResourceModel: *m,
+	}
+	if a.TunnelInfo == nil {
+		return nil
+	}
+	rm.Local = types.StringNull()
+	if a.TunnelInfo.Local != "" {
+		rm.Local = types.StringValue(a.TunnelInfo.Local)
+	}
+	rm.Remote = stringOrNull(a.TunnelInfo.Remote)
+	rm.Ttl = types.Int32Null()
+	if a.TunnelInfo.Ttl != 0 {
+		rm.Ttl = int32OrNull(a.TunnelInfo.Ttl)
+	}
+	rm.Key = types.Int64Null()
+	if a.TunnelInfo.Key != nil {
+		rm.Key = types.Int64Value(int64(*a.TunnelInfo.Key))
+	}
+	rm.PmtuDisc = types.BoolValue(a.TunnelInfo.PmtuDisc)
+	return rm
+}
+
+func (r *IfTunnelResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resourceModel, internal, diags := r.convertIfTunnelResourceModel(ctx, &req.Plan)
+	resp.Diagnostics.Append(*diags...)
+
+	if diags.HasError() {
+		tflog.Error(ctx, "Exiting due to error")
+		return
+	}
+
+	err := createIf(r.hostData, internal, func() error {
+		_, err := linuxhost_client.CreateIfTunnel(r.hostData.Client, internal)
+		return err
+	})
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed creating "+r.kind+" tunnel", err.Error())
+		return
+	}
+	r.hostData.Interfaces.Clear()
+
+	resp.Diagnostics.Append(IfToState(
+		r.hostData, resourceModel, ctx, &resp.State,
+		convertTunnelIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, r.convertIfTunnelResourceModel, true)...)
+}
+
+func (r *IfTunnelResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resourceModel, _, diags := r.convertIfTunnelResourceModel(ctx, &req.State)
+
+	resp.Diagnostics.Append(*diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(IfToState(
+		r.hostData, resourceModel, ctx, &resp.State,
+		convertTunnelIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, nil, req.Private, r.convertIfTunnelResourceModel, false)...)
+}
+
+func (r *IfTunnelResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	desiredM, desired, diagsA := r.convertIfTunnelResourceModel(ctx, &req.Plan)
+	resp.Diagnostics.Append(*diagsA...)
+	_, state, diagsB := r.convertIfTunnelResourceModel(ctx, &req.State)
+
+	resp.Diagnostics.Append(*diagsB...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(UpdateIf(r.hostData, desired, state, ctx, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.hostData.Interfaces.Clear()
+
+	resp.Diagnostics.Append(IfToState(
+		r.hostData, desiredM, ctx, &resp.State,
+		convertTunnelIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, r.convertIfTunnelResourceModel, true)...)
+}
+
+func (r *IfTunnelResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resourceModel, internal, diags := r.convertIfTunnelResourceModel(ctx, &req.State)
+	resp.Diagnostics.Append(*diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(IfRemovePersistence(r.hostData, internal)...)
+	linuxhost_client.DeleteInterfaceInNetns(r.hostData.Client, resourceModel.Netns.ValueString(), resourceModel.Name.ValueString())
+}
+
+func (r *IfTunnelResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ImportIf(ctx, r.hostData, r.kind, req, resp)
+}
@@ -2,16 +2,21 @@ package provider
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"terraform-provider-linuxhost/linuxhost_client"
 	models "terraform-provider-linuxhost/models"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.ResourceWithConfigure = &IfVethResource{}
+var _ resource.ResourceWithImportState = &IfVethResource{}
 var _ IsLinuxhostIFResource = &IfVethResource{}
 
 type IfVethResource struct {
@@ -46,7 +51,7 @@ func (r *IfVethResource) Schema(ctx context.Context, req resource.SchemaRequest,
 		},
 	}
 	resp.Schema = schema.Schema{
-		MarkdownDescription: "A Veth interface pair",
+		MarkdownDescription: "A Veth interface pair. With `persistence` the local end creates the pair, netplan also needs the peer end persisted.",
 		Version:             1,
 		Attributes:          attributes,
 	}
@@ -84,6 +89,9 @@ func extractIfVethResourceModel(ctx context.Context, getter Getter) (*models.IfV
 		},
 		// IfCommon: *internalBase,
 	}
+	internal.Local.PeerName = internal.Peer.Name
+	internal.Local.Local = true
+	internal.Peer.PeerName = internal.Local.Name
 	return resourceModel, internal, &diags
 }
 
@@ -93,22 +101,24 @@ func IfVethToState(
 	ctx context.Context,
 	setter Setter,
 ) diag.Diagnostics {
-	adapters, _ := linuxhost_client.ReadAdapters(hostData)
-	interfaceDescriptionLocal := adapters.GetByName(resourceModel.Local.Name.ValueString())
+	// Both ends may live in different namespaces
+	adaptersLocal, _ := linuxhost_client.ReadAdaptersInNetns(hostData, resourceModel.Local.Netns.ValueString())
+	interfaceDescriptionLocal := adaptersLocal.GetByName(resourceModel.Local.Name.ValueString())
 	if interfaceDescriptionLocal == nil {
 		tflog.Debug(ctx, "interfaceDescriptionLocal is nil! Was looking for "+resourceModel.Local.Name.ValueString())
 	}
-	commonResourceModelLocal, diagsLocal := BuildIfResourceModelFromInternal(ctx, &adapters, interfaceDescriptionLocal)
+	commonResourceModelLocal, diagsLocal := BuildIfResourceModelFromInternal(ctx, &adaptersLocal, interfaceDescriptionLocal)
 	if diagsLocal.HasError() {
 		tflog.Debug(ctx, "diagsLocal has error")
 		return diagsLocal
 	}
 
-	interfaceDescriptionPeer := adapters.GetByName(resourceModel.Peer.Name.ValueString())
+	adaptersPeer, _ := linuxhost_client.ReadAdaptersInNetns(hostData, resourceModel.Peer.Netns.ValueString())
+	interfaceDescriptionPeer := adaptersPeer.GetByName(resourceModel.Peer.Name.ValueString())
 	if interfaceDescriptionPeer == nil {
 		tflog.Debug(ctx, "interfaceDescriptionPeer is nil!")
 	}
-	commonResourceModelPeer, diagsPeer := BuildIfResourceModelFromInternal(ctx, &adapters, interfaceDescriptionPeer)
+	commonResourceModelPeer, diagsPeer := BuildIfResourceModelFromInternal(ctx, &adaptersPeer, interfaceDescriptionPeer)
 	if diagsPeer.HasError() {
 		tflog.Debug(ctx, "diagsPeer has error")
 		return diagsPeer
@@ -128,6 +138,10 @@ func IfVethToState(
 	}
 
 	tflog.Debug(ctx, "setting final model for vethToState")
+	commonResourceModelLocal.Netns = resourceModel.Local.Netns
+	commonResourceModelPeer.Netns = resourceModel.Peer.Netns
+	commonResourceModelLocal.Persistence = resourceModel.Local.Persistence
+	commonResourceModelPeer.Persistence = resourceModel.Peer.Persistence
 
 	finalModel := &models.IfVethPairResourceModel{
 		Local: &models.IfVethPeerResourceModel{
@@ -156,7 +170,10 @@ func (r *IfVethResource) Create(ctx context.Context, req resource.CreateRequest,
 	}
 
 	tflog.Debug(ctx, "veth pair about to create")
-	_, err := linuxhost_client.CreateIfVeth(r.hostData.Client, internal)
+	err := createIf(r.hostData, &internal.Local, func() error {
+		_, err := linuxhost_client.CreateIfVeth(r.hostData.Client, internal)
+		return err
+	})
 	tflog.Debug(ctx, "veth pair created")
 	r.hostData.Interfaces.Clear()
 	if r.hostData.Interfaces != nil {
@@ -171,6 +188,7 @@ func (r *IfVethResource) Create(ctx context.Context, req resource.CreateRequest,
 
 	resp.Diagnostics.Append(IfVethToState(
 		r.hostData, resourceModel, ctx, &resp.State)...)
+	resp.Diagnostics.Append(r.persist(ctx, &resp.State, &req.Config, resp.Private, true)...)
 
 	// resp.Diagnostics.Append(IfToState(
 	// 	r.hostData, resourceModel.Peer, ctx, &resp.State,
@@ -186,6 +204,21 @@ func (r *IfVethResource) Read(ctx context.Context, req resource.ReadRequest, res
 
 	resp.Diagnostics.Append(IfVethToState(
 		r.hostData, resourceModel, ctx, &resp.State)...)
+	resp.Diagnostics.Append(r.persist(ctx, &resp.State, nil, req.Private, false)...)
+}
+
+// persist saves or verifies the persisted configuration of both ends, see IfPersist
+func (r *IfVethResource) persist(ctx context.Context, state *tfsdk.State, config *tfsdk.Config, private privateState, save bool) diag.Diagnostics {
+	if state.Raw.IsNull() {
+		return diag.Diagnostics{}
+	}
+	_, internal, diags := extractIfVethResourceModel(ctx, state)
+	if diags.HasError() {
+		return *diags
+	}
+	diags.Append(persistIf(r.hostData, ctx, &internal.Local, state, config, private, path.Root("local").AtName("persistence"), save)...)
+	diags.Append(persistIf(r.hostData, ctx, &internal.Peer, state, config, private, path.Root("peer").AtName("persistence"), save)...)
+	return *diags
 }
 
 func (r *IfVethResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
@@ -197,10 +230,20 @@ func (r *IfVethResource) Update(ctx context.Context, req resource.UpdateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	UpdateIf(r.hostData, &desired.Local, &state.Local, ctx, &resp.State)
-	UpdateIf(r.hostData, &desired.Peer, &state.Peer, ctx, &resp.State)
+	resp.Diagnostics.Append(UpdateIf(r.hostData, &desired.Local, &state.Local, ctx, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(UpdateIf(r.hostData, &desired.Peer, &state.Peer, ctx, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, desiredM)...)
+	r.hostData.Interfaces.Clear()
+
+	resp.Diagnostics.Append(IfVethToState(
+		r.hostData, desiredM, ctx, &resp.State)...)
+	resp.Diagnostics.Append(r.persist(ctx, &resp.State, &req.Config, resp.Private, true)...)
 
 }
 func (r *IfVethResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -208,8 +251,43 @@ func (r *IfVethResource) Delete(ctx context.Context, req resource.DeleteRequest,
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
-	resourceModel, _, diags := extractIfVethResourceModel(ctx, &req.State)
+	resourceModel, internal, diags := extractIfVethResourceModel(ctx, &req.State)
 	resp.Diagnostics.Append(*diags...)
-	linuxhost_client.DeleteInterface(r.hostData.Client, resourceModel.Local.Name.ValueString())
+	resp.Diagnostics.Append(IfRemovePersistence(r.hostData, &internal.Local)...)
+	resp.Diagnostics.Append(IfRemovePersistence(r.hostData, &internal.Peer)...)
+	linuxhost_client.DeleteInterfaceInNetns(r.hostData.Client, resourceModel.Local.Netns.ValueString(), resourceModel.Local.Name.ValueString())
+
+}
 
+// ImportState imports the pair by "<local>:<peer>", either end may be
+// "<netns>/<name>"
+func (r *IfVethResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	local, peer, found := strings.Cut(req.ID, ":")
+	if !found {
+		resp.Diagnostics.AddError("Invalid import id", "Expected <local>:<peer>, e.g. veth0:veth1, got "+req.ID)
+		return
+	}
+	ends := map[string]string{"local": local, "peer": peer}
+	for _, end := range []string{"local", "peer"} {
+		netns, name := splitIfImportId(ends[end])
+		adapter, diags := importedAdapter(r.hostData, netns, name, "veth")
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		// The peer is only known by index in another namespace
+		other := peer
+		if end == "peer" {
+			other = local
+		}
+		_, otherName := splitIfImportId(other)
+		if linked := adapter.LinkedInterface; linked != nil && !strings.HasPrefix(*linked, "if") && *linked != otherName {
+			resp.Diagnostics.AddError("Not a veth pair", fmt.Sprintf("The peer of %s is %s, not %s.", name, *linked, otherName))
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(end).AtName("name"), name)...)
+		if netns != "" {
+			resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(end).AtName("netns"), netns)...)
+		}
+	}
 }
@@ -7,7 +7,6 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
-	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
@@ -17,6 +16,7 @@ import (
 )
 
 var _ resource.ResourceWithConfigure = &IfVlanResource{}
+var _ resource.ResourceWithImportState = &IfVlanResource{}
 var _ IsLinuxhostIFResource = &IfVlanResource{}
 
 func NewIfVlanResource() resource.Resource {
@@ -116,7 +116,10 @@ func (r *IfVlanResource) Create(ctx context.Context, req resource.CreateRequest,
 		return
 	}
 
-	_, err := linuxhost_client.CreateIfVlan(r.hostData.Client, internal)
+	err := createIf(r.hostData, internal, func() error {
+		_, err := linuxhost_client.CreateIfVlan(r.hostData.Client, internal)
+		return err
+	})
 
 	if err != nil {
 		resp.Diagnostics.AddError("Failed creating vlan", err.Error())
@@ -132,6 +135,7 @@ func (r *IfVlanResource) Create(ctx context.Context, req resource.CreateRequest,
 	resp.Diagnostics.Append(IfToState(
 		r.hostData, resourceModel, ctx, &resp.State,
 		convertVlanIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfVlanResourceModel, true)...)
 }
 func (r *IfVlanResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data models.IfVlanResourceModel
@@ -147,18 +151,27 @@ func (r *IfVlanResource) Update(ctx context.Context, req resource.UpdateRequest,
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	UpdateIf(r.hostData, desired, state, ctx, &resp.State)
+	resp.Diagnostics.Append(UpdateIf(r.hostData, desired, state, ctx, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.hostData.Interfaces.Clear()
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, desiredM)...)
+	resp.Diagnostics.Append(IfToState(
+		r.hostData, desiredM, ctx, &resp.State,
+		convertVlanIf)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfVlanResourceModel, true)...)
 }
 func (r *IfVlanResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data models.IfVlanResourceModel
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
-	resourceModel, _, diags := convertIfVlanResourceModel(ctx, &req.State)
+	resourceModel, internal, diags := convertIfVlanResourceModel(ctx, &req.State)
 	resp.Diagnostics.Append(*diags...)
-	linuxhost_client.DeleteInterface(r.hostData.Client, resourceModel.Name.ValueString())
+	resp.Diagnostics.Append(IfRemovePersistence(r.hostData, internal)...)
+	linuxhost_client.DeleteInterfaceInNetns(r.hostData.Client, resourceModel.Netns.ValueString(), resourceModel.Name.ValueString())
 }
 
 func (r *IfVlanResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -172,11 +185,11 @@ func (r *IfVlanResource) Read(ctx context.Context, req resource.ReadRequest, res
 	resp.Diagnostics.Append(IfToState(
 		r.hostData, resourceModel, ctx, &resp.State,
 		convertVlanIf)...)
-
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, nil, req.Private, convertIfVlanResourceModel, false)...)
 }
 
 func (r *IfVlanResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	ImportIf(ctx, r.hostData, "vlan", req, resp)
 }
 
 // func (r *IfVlanResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
@@ -7,17 +7,26 @@ import (
 	models "terraform-provider-linuxhost/models"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.ResourceWithConfigure = &IfVxlanResource{}
+var _ resource.ResourceWithImportState = &IfVxlanResource{}
+var _ resource.ResourceWithValidateConfig = &IfVxlanResource{}
 
 // var _ resource.ResourceWithUpgradeState = &IfVxlanResource{}
 
@@ -52,6 +61,73 @@ func (r *IfVxlanResource) Schema(ctx context.Context, req resource.SchemaRequest
 		Computed: true,
 		Default:  int32default.StaticInt32(4789),
 	}
+	attributes["local"] = schema.StringAttribute{
+		MarkdownDescription: "The source address of outgoing packets",
+		Optional:            true,
+		PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	attributes["remote"] = schema.StringAttribute{
+		MarkdownDescription: "The unicast destination address of the remote VTEP. Conflicts with `group`.",
+		Optional:            true,
+		PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	attributes["group"] = schema.StringAttribute{
+		MarkdownDescription: "The multicast group to join. Conflicts with `remote` and requires `dev`.",
+		Optional:            true,
+		PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	attributes["dev"] = schema.StringAttribute{
+		MarkdownDescription: "The underlay device used for the tunnel endpoint, e.g. 'eth0'",
+		Optional:            true,
+		PlanModifiers:       []planmodifier.String{stringplanmodifier.RequiresReplace()},
+	}
+	attributes["ttl"] = schema.Int32Attribute{
+		MarkdownDescription: "The ttl of outgoing packets. If unspecified the kernel chooses it automatically.",
+		Optional:            true,
+		PlanModifiers:       []planmodifier.Int32{int32planmodifier.RequiresReplace()},
+		Validators:          []validator.Int32{int32validator.Between(1, 255)},
+	}
+	attributes["tos"] = schema.Int32Attribute{
+		MarkdownDescription: "The TOS of outgoing packets. The value 1 inherits the TOS of the inner packet.",
+		Optional:            true,
+		PlanModifiers:       []planmodifier.Int32{int32planmodifier.RequiresReplace()},
+		Validators:          []validator.Int32{int32validator.Between(1, 255)},
+	}
+	attributes["learning"] = schema.BoolAttribute{
+		MarkdownDescription: "Whether unknown source addresses are learned into the forwarding database. Set to false for `nolearning`.",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(true),
+		PlanModifiers:       []planmodifier.Bool{boolplanmodifier.RequiresReplace()},
+	}
+	attributes["srcport_min"] = schema.Int32Attribute{
+		MarkdownDescription: "The lowest UDP source port. Must be specified together with `srcport_max`.",
+		Optional:            true,
+		PlanModifiers:       []planmodifier.Int32{int32planmodifier.RequiresReplace()},
+		Validators:          []validator.Int32{int32validator.Between(1, 65535)},
+	}
+	attributes["srcport_max"] = schema.Int32Attribute{
+		MarkdownDescription: "The highest UDP source port. Must be specified together with `srcport_min`.",
+		Optional:            true,
+		PlanModifiers:       []planmodifier.Int32{int32planmodifier.RequiresReplace()},
+		Validators:          []validator.Int32{int32validator.Between(1, 65535)},
+	}
+	attributes["udpcsum"] = schema.BoolAttribute{
+		MarkdownDescription: "Whether UDP checksums are calculated for IPv4 transport. If unspecified the kernel default is used.",
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers: []planmodifier.Bool{
+			boolplanmodifier.UseStateForUnknown(),
+			boolplanmodifier.RequiresReplace(),
+		},
+	}
+	attributes["external"] = schema.BoolAttribute{
+		MarkdownDescription: "Whether the interface runs in external mode, where the tunnel endpoints are provided by routes or tc rules",
+		Optional:            true,
+		Computed:            true,
+		Default:             booldefault.StaticBool(false),
+		PlanModifiers:       []planmodifier.Bool{boolplanmodifier.RequiresReplace()},
+	}
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "A vxlan interface",
 		Version:             1,
@@ -63,6 +139,42 @@ func (r *IfVxlanResource) Configure(ctx context.Context, req resource.ConfigureR
 	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
 }
 
+func (r *IfVxlanResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config models.IfVxlanResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !config.Remote.IsNull() && !config.Group.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("group"),
+			"Conflicting destination",
+			"Only one of 'remote' and 'group' may be provided.",
+		)
+	}
+	if !config.Group.IsNull() && config.Dev.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("dev"),
+			"Missing dev",
+			"A multicast 'group' requires the underlay 'dev'.",
+		)
+	}
+	if config.SrcPortMin.IsNull() != config.SrcPortMax.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("srcport_min"),
+			"Incomplete source port range",
+			"The 'srcport_min' and 'srcport_max' attributes must be provided together.",
+		)
+	} else if !config.SrcPortMin.IsUnknown() && !config.SrcPortMax.IsUnknown() && config.SrcPortMin.ValueInt32() > config.SrcPortMax.ValueInt32() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("srcport_max"),
+			"Invalid source port range",
+			"The 'srcport_max' attribute must not be lower than 'srcport_min'.",
+		)
+	}
+}
+
 func convertIfVxlanResourceModel(ctx context.Context, getter Getter) (*models.IfVxlanResourceModel, *linuxhost_client.IfVxlan, *diag.Diagnostics) {
 	// var resourceModel models.IfVxlanResourceModel
 	// resp.Diagnostics.Append(req.Plan.Get(ctx, &resourceModel)...)
@@ -87,6 +199,18 @@ func convertIfVxlanResourceModel(ctx context.Context, getter Getter) (*models.If
 		IfCommon: *internalBase,
 		Vni:      uint32(resource.Vni.ValueInt64()),
 		Port:     uint32(resource.Port.ValueInt32()),
+
+		Local:      resource.Local.ValueString(),
+		Remote:     resource.Remote.ValueString(),
+		Group:      resource.Group.ValueString(),
+		Dev:        resource.Dev.ValueString(),
+		Ttl:        uint32(resource.Ttl.ValueInt32()),
+		Tos:        uint32(resource.Tos.ValueInt32()),
+		Learning:   resource.Learning.IsNull() || resource.Learning.IsUnknown() || resource.Learning.ValueBool(),
+		SrcPortMin: uint32(resource.SrcPortMin.ValueInt32()),
+		SrcPortMax: uint32(resource.SrcPortMax.ValueInt32()),
+		UdpCsum:    boolOrNil(resource.UdpCsum),
+		External:   resource.External.ValueBool(),
 	}
 
 	return resource, internal, diags
@@ -97,6 +221,31 @@ func convertVxlanIf(m *models.IfCommonResourceModel, a *linuxhost_client.Adapter
 	}
 	rm.Vni = int64OrNull(a.Vni)
 	rm.Port = int32OrNull(a.Port)
+	if a.VxlanInfo == nil {
+		return rm
+	}
+	info := a.VxlanInfo
+	rm.Local = nonEmptyStringOrNull(info.Local)
+	rm.Remote = nonEmptyStringOrNull(info.Remote)
+	rm.Group = nonEmptyStringOrNull(info.Group)
+	rm.Dev = nonEmptyStringOrNull(info.Dev)
+	rm.Ttl = types.Int32Null()
+	if info.Ttl != 0 {
+		rm.Ttl = int32OrNull(info.Ttl)
+	}
+	rm.Tos = types.Int32Null()
+	if info.Tos != 0 {
+		rm.Tos = int32OrNull(info.Tos)
+	}
+	rm.Learning = types.BoolValue(info.Learning)
+	rm.SrcPortMin = types.Int32Null()
+	rm.SrcPortMax = types.Int32Null()
+	if info.SrcPortMin != 0 || info.SrcPortMax != 0 {
+		rm.SrcPortMin = int32OrNull(info.SrcPortMin)
+		rm.SrcPortMax = int32OrNull(info.SrcPortMax)
+	}
+	rm.UdpCsum = types.BoolValue(info.UdpCsum)
+	rm.External = types.BoolValue(info.External)
 	return rm
 }
 
@@ -115,7 +264,10 @@ func (r *IfVxlanResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	_, err := linuxhost_client.CreateIfVXLAN(r.hostData.Client, internal)
+	err := createIf(r.hostData, internal, func() error {
+		_, err := linuxhost_client.CreateIfVXLAN(r.hostData.Client, internal)
+		return err
+	})
 
 	if err != nil {
 		resp.Diagnostics.AddError("Failed creating vxlan", err.Error())
@@ -144,6 +296,7 @@ func (r *IfVxlanResource) Create(ctx context.Context, req resource.CreateRequest
 	// }
 	// linuxhost_client.SetVxlan(r.hostData.Client, &data, nil)
 	// r.makeStateRefresher(ctx, &resp.State, &resp.Diagnostics).InState(data, "present")
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfVxlanResourceModel, true)...)
 }
 func (r *IfVxlanResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var data models.IfVxlanResourceModel
@@ -159,21 +312,30 @@ func (r *IfVxlanResource) Update(ctx context.Context, req resource.UpdateRequest
 	if resp.Diagnostics.HasError() {
 		return
 	}
-	UpdateIf(r.hostData, desired, state, ctx, &resp.State)
+	resp.Diagnostics.Append(UpdateIf(r.hostData, desired, state, ctx, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.hostData.Interfaces.Clear()
 
-	resp.Diagnostics.Append(resp.State.Set(ctx, desiredM)...)
+	resp.Diagnostics.Append(IfToState(
+		r.hostData, desiredM, ctx, &resp.State,
+		convertVxlanIf)...)
 
 	// linuxhost_client.SetVxlan(r.hostData.Client, &data, nil)
 	// r.makeStateRefresher(ctx, &resp.State, &resp.Diagnostics).InState(data, "present")
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfVxlanResourceModel, true)...)
 }
 func (r *IfVxlanResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
 	var data models.IfVxlanResourceModel
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
-	resourceModel, _, diags := convertIfVxlanResourceModel(ctx, &req.State)
+	resourceModel, internal, diags := convertIfVxlanResourceModel(ctx, &req.State)
 	resp.Diagnostics.Append(*diags...)
-	linuxhost_client.DeleteInterface(r.hostData.Client, resourceModel.Name.ValueString())
+	resp.Diagnostics.Append(IfRemovePersistence(r.hostData, internal)...)
+	linuxhost_client.DeleteInterfaceInNetns(r.hostData.Client, resourceModel.Netns.ValueString(), resourceModel.Name.ValueString())
 }
 
 func (r *IfVxlanResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
@@ -187,9 +349,9 @@ func (r *IfVxlanResource) Read(ctx context.Context, req resource.ReadRequest, re
 	resp.Diagnostics.Append(IfToState(
 		r.hostData, resourceModel, ctx, &resp.State,
 		convertVxlanIf)...)
-
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, nil, req.Private, convertIfVxlanResourceModel, false)...)
 }
 
 func (r *IfVxlanResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+	ImportIf(ctx, r.hostData, "vxlan", req, resp)
 }
@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.ResourceWithConfigure = &IfWireguardResource{}
+var _ resource.ResourceWithImportState = &IfWireguardResource{}
+var _ resource.ResourceWithModifyPlan = &IfWireguardResource{}
+var _ IsLinuxhostIFResource = &IfWireguardResource{}
+
+func NewIfWireguardResource() resource.Resource {
+	return &IfWireguardResource{}
+}
+
+type IfWireguardResource struct {
+	LinuxhostCommonResource
+}
+
+func (r *IfWireguardResource) GetHostData() *linuxhost_client.HostData {
+	return r.hostData
+}
+
+func (r *IfWireguardResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_if_wireguard"
+}
+
+func (r *IfWireguardResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	attributes := commonInterfaceSchema()
+	attributes["listen_port"] = schema.Int32Attribute{
+		MarkdownDescription: "The UDP port to listen on. If unspecified a random port is chosen by the kernel.",
+		Optional:            true,
+		Computed:            true,
+		PlanModifiers: []planmodifier.Int32{
+			int32planmodifier.UseStateForUnknown(),
+		},
+		Validators: []validator.Int32{
+			int32validator.Between(1, 65535),
+		},
+	}
+	attributes["fwmark"] = schema.Int64Attribute{
+		MarkdownDescription: "The firewall mark applied to outgoing packets, 0 disables it.",
+		Optional:            true,
+		Computed:            true,
+		Default:             int64default.StaticInt64(0),
+	}
+	attributes["private_key"] = schema.StringAttribute{
+		MarkdownDescription: "The base64 private key. If unspecified a key is generated on the host with `wg genkey`.",
+		Optional:            true,
+		Computed:            true,
+		Sensitive:           true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+	attributes["public_key"] = schema.StringAttribute{
+		MarkdownDescription: "The base64 public key derived from the private key, to be given to peers.",
+		Computed:            true,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.UseStateForUnknown(),
+		},
+	}
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A WireGuard interface",
+		Version:             1,
+		Attributes:          attributes,
+	}
+}
+
+func (r *IfWireguardResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *IfWireguardResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+	var planned, state models.IfWireguardResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &planned)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	/// The public key only changes along with the private key
+	if planned.PrivateKey.IsUnknown() || !planned.PrivateKey.Equal(state.PrivateKey) {
+		tflog.Debug(ctx, "WireGuard private key changed, public key will be recomputed")
+		planned.PublicKey = types.StringUnknown()
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &planned)...)
+	}
+}
+
+func convertIfWireguardResourceModel(ctx context.Context, getter Getter) (*models.IfWireguardResourceModel, *linuxhost_client.IfWireguard, *diag.Diagnostics) {
+	tflog.Debug(ctx, "converting IfWireguardResourceModel")
+	resource, internalBase, diags := ExtractIfResourceModel[*models.IfWireguardResourceModel](ctx, getter)
+	if diags.HasError() {
+		tflog.Debug(ctx, "Error converting IfWireguardResourceModel")
+		return nil, nil, diags
+	}
+	tflog.Debug(ctx, "did base conversion successfully")
+
+	internal := &linuxhost_client.IfWireguard{
+		IfCommon:   *internalBase,
+		ListenPort: uint32(resource.ListenPort.ValueInt32()),
+		FwMark:     uint32(resource.FwMark.ValueInt64()),
+		PrivateKey: resource.PrivateKey.ValueString(),
+	}
+	return resource, internal, diags
+}
+
+func convertWireguardIf(dump *linuxhost_client.WireguardDump) func(m *models.IfCommonResourceModel, a *linuxhost_client.AdapterInfo, all *linuxhost_client.AdapterInfoSlice) *models.IfWireguardResourceModel {
+	return func(m *models.IfCommonResourceModel, a *linuxhost_client.AdapterInfo, all *linuxhost_client.AdapterInfoSlice) *models.IfWireguardResourceModel {
+		rm := &models.IfWireguardResourceModel{
+			IfCommonResourceModel: *m,
+			ListenPort:            types.Int32Null(),
+			FwMark:                types.Int64Value(0),
+			PrivateKey:            types.StringNull(),
+			PublicKey:             types.StringNull(),
+		}
+		if dump == nil {
+			return rm
+		}
+		rm.ListenPort = types.Int32Value(int32(dump.ListenPort))
+		rm.FwMark = types.Int64Value(int64(dump.FwMark))
+		rm.PrivateKey = stringOrNull(dump.PrivateKey)
+		rm.PublicKey = stringOrNull(dump.PublicKey)
+		return rm
+	}
+}
+
+func (r *IfWireguardResource) toState(ctx context.Context, resourceModel *models.IfWireguardResourceModel, setter Setter) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	name := resourceModel.Name.ValueString()
+	netns := resourceModel.Netns.ValueString()
+	adapters, _ := linuxhost_client.ReadAdaptersInNetns(r.hostData, netns)
+	var dump *linuxhost_client.WireguardDump
+	if adapters.GetByName(name) != nil {
+		var err error
+		dump, err = linuxhost_client.ReadWireguardInNetns(r.hostData.Client, netns, name)
+		if err != nil {
+			diags.AddError("Failed reading WireGuard interface "+name, err.Error())
+			return diags
+		}
+	}
+	diags.Append(IfToState(
+		r.hostData, resourceModel, ctx, setter,
+		convertWireguardIf(dump))...)
+	return diags
+}
+
+func (r *IfWireguardResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	resourceModel, internal, diags := convertIfWireguardResourceModel(ctx, &req.Plan)
+	resp.Diagnostics.Append(*diags...)
+
+	if diags.HasError() {
+		tflog.Error(ctx, "Exiting due to error")
+		return
+	}
+
+	_, err := linuxhost_client.CreateIfWireguard(r.hostData.Client, internal)
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed creating WireGuard interface", err.Error())
+		return
+	}
+	r.hostData.Interfaces.Clear()
+
+	resp.Diagnostics.Append(r.toState(ctx, resourceModel, &resp.State)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfWireguardResourceModel, true)...)
+}
+
+func (r *IfWireguardResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	resourceModel, _, diags := convertIfWireguardResourceModel(ctx, &req.State)
+
+	resp.Diagnostics.Append(*diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.toState(ctx, resourceModel, &resp.State)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, nil, req.Private, convertIfWireguardResourceModel, false)...)
+}
+
+func (r *IfWireguardResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	desiredM, desired, diagsA := convertIfWireguardResourceModel(ctx, &req.Plan)
+	resp.Diagnostics.Append(*diagsA...)
+	_, state, diagsB := convertIfWireguardResourceModel(ctx, &req.State)
+
+	resp.Diagnostics.Append(*diagsB...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if desired.PrivateKey != state.PrivateKey || desired.ListenPort != state.ListenPort || desired.FwMark != state.FwMark {
+		if err := linuxhost_client.IfSetWireguard(r.hostData.Client, desired); err != nil {
+			resp.Diagnostics.AddError("Failed updating WireGuard interface", err.Error())
+			return
+		}
+	}
+	resp.Diagnostics.Append(UpdateIf(r.hostData, desired, state, ctx, &resp.State)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.hostData.Interfaces.Clear()
+
+	resp.Diagnostics.Append(r.toState(ctx, desiredM, &resp.State)...)
+	resp.Diagnostics.Append(IfPersist(r.hostData, ctx, &resp.State, &req.Config, resp.Private, convertIfWireguardResourceModel, true)...)
+}
+
+func (r *IfWireguardResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resourceModel, internal, diags := convertIfWireguardResourceModel(ctx, &req.State)
+	resp.Diagnostics.Append(*diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(IfRemovePersistence(r.hostData, internal)...)
+	linuxhost_client.DeleteInterfaceInNetns(r.hostData.Client, resourceModel.Netns.ValueString(), resourceModel.Name.ValueString())
+}
+
+func (r *IfWireguardResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	ImportIf(ctx, r.hostData, "wireguard", req, resp)
+}
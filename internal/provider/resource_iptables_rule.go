@@ -0,0 +1,224 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &IptablesRuleResource{}
+
+func NewIptablesRuleResource() resource.Resource {
+	return &IptablesRuleResource{}
+}
+
+type IptablesRuleResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *IptablesRuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_iptables_rule"
+}
+
+func (r *IptablesRuleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "An iptables or ip6tables rule for hosts without nftables. The rule is tagged with a comment the provider adds, so rules of other tools are left alone.",
+		Attributes: map[string]schema.Attribute{
+			"table": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("filter"),
+				MarkdownDescription: "The table of the rule. Valid options: 'filter', 'nat', 'mangle', 'raw', 'security'. Defaults to 'filter'.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(linuxhost_client.IptablesTables...),
+				},
+			},
+			"chain": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The chain of the rule, e.g. 'INPUT'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"rule": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The rule specification in shell syntax without a comment match, e.g. '-p tcp --dport 22 -j ACCEPT'. Changes replace the rule in place.",
+			},
+			"position": schema.Int32Attribute{
+				Optional:            true,
+				MarkdownDescription: "The position to insert the rule at, starting with 1. The rule is appended if unspecified and replaced if it moved.",
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int32{
+					int32validator.AtLeast(1),
+				},
+			},
+			"ipv6": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Whether the rule is an ip6tables rule",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The comment identifying the rule",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *IptablesRuleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func iptablesRuleFromModel(data *models.IptablesRuleModel) *linuxhost_client.IptablesRule {
+	return &linuxhost_client.IptablesRule{
+		Table:    data.Table.ValueString(),
+		Chain:    data.Chain.ValueString(),
+		Spec:     data.Rule.ValueString(),
+		Comment:  data.Comment.ValueString(),
+		Position: int(data.Position.ValueInt32()),
+		IPv6:     data.Ipv6.ValueBool(),
+	}
+}
+
+// readState finds the rule by its comment and checks it with -C, as
+// iptables-save normalizes the rule a changed one reports that form
+func (r *IptablesRuleResource) readState(ctx context.Context, data *models.IptablesRuleModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
+	found, err := linuxhost_client.ReadIptablesRule(r.hostData.Client, data.Table.ValueString(), data.Comment.ValueString(), data.Ipv6.ValueBool())
+	if err != nil {
+		Diagnostics.AddError("Failed reading iptables rules", err.Error())
+		return
+	}
+	if found == nil {
+		if expect == "present" {
+			Diagnostics.AddError("Didn't find iptables rule", "")
+		} else {
+			State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	current := *data
+	current.Chain = types.StringValue(found.Chain)
+	if !data.Position.IsNull() {
+		current.Position = types.Int32Value(int32(found.Position))
+	}
+	matches, err := linuxhost_client.CheckIptablesRule(r.hostData.Client, iptablesRuleFromModel(data))
+	if err != nil {
+		Diagnostics.AddError("Failed checking iptables rule", err.Error())
+		return
+	}
+	if !matches {
+		if expect == "present" {
+			Diagnostics.AddError("Rule not applied", "iptables reported no error but the rule is '"+found.Spec+"'.")
+			return
+		}
+		current.Rule = types.StringValue(found.Spec)
+	}
+	Diagnostics.Append(State.Set(ctx, &current)...)
+}
+
+func (r *IptablesRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.IptablesRuleModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	comment, err := linuxhost_client.NewRuleComment()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed generating rule comment", err.Error())
+		return
+	}
+	data.Comment = types.StringValue(comment)
+	if err := linuxhost_client.AddIptablesRule(r.hostData.Client, iptablesRuleFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed creating iptables rule", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *IptablesRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.IptablesRuleModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "any")
+}
+
+// Update replaces the rule at its current position
+func (r *IptablesRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.IptablesRuleModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, err := linuxhost_client.ReadIptablesRule(r.hostData.Client, plan.Table.ValueString(), plan.Comment.ValueString(), plan.Ipv6.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed reading iptables rules", err.Error())
+		return
+	}
+	if found == nil {
+		resp.Diagnostics.AddError("Didn't find iptables rule", "The rule was removed while it was updated.")
+		return
+	}
+	if err := linuxhost_client.ReplaceIptablesRule(r.hostData.Client, iptablesRuleFromModel(&plan), found.Position); err != nil {
+		resp.Diagnostics.AddError("Failed updating iptables rule", err.Error())
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *IptablesRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.IptablesRuleModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	found, err := linuxhost_client.ReadIptablesRule(r.hostData.Client, data.Table.ValueString(), data.Comment.ValueString(), data.Ipv6.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed reading iptables rules", err.Error())
+		return
+	}
+	if found == nil {
+		return
+	}
+	if err := linuxhost_client.DeleteIptablesRule(r.hostData.Client, found, found.Position); err != nil {
+		resp.Diagnostics.AddError("Failed to delete iptables rule", err.Error())
+	}
+}
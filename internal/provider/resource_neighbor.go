@@ -0,0 +1,232 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &NeighborResource{}
+var _ resource.ResourceWithValidateConfig = &NeighborResource{}
+
+func NewNeighborResource() resource.Resource {
+	return &NeighborResource{}
+}
+
+type NeighborResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *NeighborResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_neighbor"
+}
+
+func (r *NeighborResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A static ARP (IPv4) or NDP (IPv6) neighbor entry",
+		Attributes: map[string]schema.Attribute{
+			"address": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The IPv4 or IPv6 address of the neighbor",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"dev": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The interface the neighbor is attached to, e.g. 'eth0'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"lladdr": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The link layer address of the neighbor. Required unless `proxy` is set.",
+			},
+			"state": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The entry state. Valid options: 'permanent', 'noarp', 'reachable'. Defaults to 'permanent' and is always null for proxy entries.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("permanent", "noarp", "reachable"),
+				},
+			},
+			"proxy": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Whether this is a proxy ARP/NDP entry, answering for `address` on `dev`",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *NeighborResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *NeighborResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config models.NeighborModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.Proxy.IsUnknown() {
+		return
+	}
+	if config.Proxy.ValueBool() {
+		if !config.Lladdr.IsNull() || !config.State.IsNull() {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("proxy"),
+				"Unexpected attributes",
+				"Proxy entries don't support 'lladdr' or 'state'.",
+			)
+		}
+	} else if config.Lladdr.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("lladdr"),
+			"Missing lladdr",
+			"The 'lladdr' attribute is required unless 'proxy' is set.",
+		)
+	}
+}
+
+func neighborFromModel(data *models.NeighborModel) *linuxhost_client.Neighbor {
+	state := data.State.ValueString()
+	if state == "" {
+		state = "permanent"
+	}
+	return &linuxhost_client.Neighbor{
+		Address: data.Address.ValueString(),
+		Lladdr:  data.Lladdr.ValueString(),
+		Dev:     data.Dev.ValueString(),
+		State:   state,
+		Proxy:   data.Proxy.ValueBool(),
+	}
+}
+
+func (r *NeighborResource) readState(ctx context.Context, data *models.NeighborModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
+	neighbors, err := linuxhost_client.ReadNeighbors(r.hostData.Client, data.Dev.ValueString(), data.Proxy.ValueBool())
+	if err != nil {
+		Diagnostics.AddError("Failed reading neighbors", err.Error())
+		return
+	}
+	found := linuxhost_client.FindNeighbor(neighbors, neighborFromModel(data))
+	if found != nil {
+		if expect == "absent" {
+			Diagnostics.AddError("Failed to delete", "The delete operation did not report any errors but the resource remains present in the reported state.")
+			return
+		}
+		current := &models.NeighborModel{
+			Address: data.Address,
+			Dev:     data.Dev,
+			Lladdr:  nonEmptyStringOrNull(found.Lladdr),
+			State:   nonEmptyStringOrNull(found.State),
+			Proxy:   data.Proxy,
+		}
+		// ip lists addresses in lower case
+		if strings.EqualFold(data.Lladdr.ValueString(), found.Lladdr) {
+			current.Lladdr = data.Lladdr
+		}
+		// Reachable entries age to stale and are re-validated by the kernel
+		if data.State.ValueString() == "reachable" && found.State != "permanent" && found.State != "noarp" {
+			current.State = types.StringValue("reachable")
+		}
+		Diagnostics.Append(State.Set(ctx, current)...)
+		return
+	}
+	if expect == "present" {
+		Diagnostics.AddError("Didn't find neighbor", "")
+	} else if expect == "any" || expect == "absent" {
+		State.RemoveResource(ctx)
+	} else {
+		Diagnostics.AddError("Invalid expectation", "This is an error with the provider 'linuxhost'")
+	}
+}
+
+func (r *NeighborResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.NeighborModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetNeighbor(r.hostData.Client, neighborFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed creating neighbor", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *NeighborResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.NeighborModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "any")
+}
+
+func (r *NeighborResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.NeighborModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetNeighbor(r.hostData.Client, neighborFromModel(&plan)); err != nil {
+		resp.Diagnostics.AddError("Failed updating neighbor", err.Error())
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *NeighborResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.NeighborModel
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The entries are gone with their device
+	neighbors, err := linuxhost_client.ReadNeighbors(r.hostData.Client, data.Dev.ValueString(), data.Proxy.ValueBool())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed reading neighbors", err.Error())
+		return
+	}
+	if linuxhost_client.FindNeighbor(neighbors, neighborFromModel(&data)) == nil {
+		return
+	}
+	if err := linuxhost_client.DeleteNeighbor(r.hostData.Client, neighborFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed to delete neighbor", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "absent")
+}
@@ -0,0 +1,169 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &NetnsResource{}
+var _ resource.ResourceWithImportState = &NetnsResource{}
+
+func NewNetnsResource() resource.Resource {
+	return &NetnsResource{}
+}
+
+type NetnsResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *NetnsResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_netns"
+}
+
+func (r *NetnsResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A named network namespace. The loopback interface inside the namespace is brought up on creation.",
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The name of the namespace, as found in /run/netns",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9_.-]+$`), "must only contain letters, digits, '.', '_' and '-'"),
+				},
+			},
+			"nsid": schema.Int32Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The namespace id in the root namespace. If unspecified the kernel assigns one on demand.",
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.UseStateForUnknown(),
+					int32planmodifier.RequiresReplace(),
+				},
+				Validators: []validator.Int32{
+					int32validator.AtLeast(0),
+				},
+			},
+			"interfaces": schema.SetAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "The interfaces currently inside the namespace",
+				PlanModifiers:       []planmodifier.Set{setplanmodifier.UseStateForUnknown()},
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *NetnsResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *NetnsResource) readState(ctx context.Context, data *models.NetnsModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
+	netns, err := linuxhost_client.ReadNetns(r.hostData.Client, data.Name.ValueString())
+	if err != nil {
+		Diagnostics.AddError("Failed reading network namespaces", err.Error())
+		return
+	}
+	if netns != nil {
+		if expect == "absent" {
+			Diagnostics.AddError("Failed to delete", "The delete operation did not report any errors but the resource remains present in the reported state.")
+			return
+		}
+		interfaces, diags := types.SetValueFrom(ctx, types.StringType, netns.Interfaces)
+		Diagnostics.Append(diags...)
+		current := &models.NetnsModel{
+			Name:       types.StringValue(netns.Name),
+			Nsid:       int32OrNull(netns.Nsid),
+			Interfaces: interfaces,
+		}
+		Diagnostics.Append(State.Set(ctx, current)...)
+		return
+	}
+	if expect == "present" {
+		Diagnostics.AddError("Didn't find network namespace", "")
+	} else if expect == "any" || expect == "absent" {
+		State.RemoveResource(ctx)
+	} else {
+		Diagnostics.AddError("Invalid expectation", "This is an error with the provider 'linuxhost'")
+	}
+}
+
+func (r *NetnsResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.NetnsModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	netns := &linuxhost_client.Netns{
+		Name: data.Name.ValueString(),
+		Nsid: uint32OrNil(data.Nsid),
+	}
+	if err := linuxhost_client.CreateNetns(r.hostData.Client, netns); err != nil {
+		resp.Diagnostics.AddError("Failed creating network namespace", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *NetnsResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.NetnsModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "any")
+}
+
+// Update only refreshes the state, all settable attributes require replacement
+func (r *NetnsResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.NetnsModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *NetnsResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.NetnsModel
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.DeleteNetns(r.hostData.Client, data.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete network namespace", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "absent")
+}
+
+func (r *NetnsResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
+}
@@ -29,7 +29,7 @@ import (
 
 // Ensure provider defined types fully satisfy framework interfaces.
 // var _ resource.Resource = &NetworkInterfaceResource{}
-// var _ resource.ResourceWithImportState = &NetworkInterfaceResource{}
+var _ resource.ResourceWithImportState = &NetworkInterfaceResource{}
 var _ resource.ResourceWithConfigure = &NetworkInterfaceResource{}
 var _ resource.ResourceWithUpgradeState = &NetworkInterfaceResource{}
 
@@ -117,11 +117,29 @@ func (r *NetworkInterfaceResource) Schema(ctx context.Context, req resource.Sche
 				// },
 			},
 			"dhcp": schema.StringAttribute{
-				Optional: true,
+				Optional:            true,
+				MarkdownDescription: "The DHCP client obtaining an IPv4 address for the interface. Valid options: `dhclient`, `dhcpcd`, `udhcpc` and `networkd`, which adds a runtime .network file to /run/systemd/network. The client has to be installed on the host, it runs in the background once it has a lease.",
 				Validators: []validator.String{
-					stringvalidator.OneOf("dhclient"),
+					stringvalidator.OneOf(linuxhost_client.DhcpClients...),
 				},
 			},
+			"dhcp_address": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The address leased by the DHCP client, with its prefix length",
+			},
+			"dhcp_gateway": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The gateway of the DHCP lease",
+			},
+			"dhcp_dns_servers": schema.ListAttribute{
+				ElementType:         types.StringType,
+				Computed:            true,
+				MarkdownDescription: "The DNS servers of the DHCP lease",
+			},
+			"dhcp_lease_expiry": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The time the DHCP lease expires, in RFC 3339 format",
+			},
 			// "ipv4": schema.SetAttribute{
 			// 	Optional:    true,
 			// 	ElementType: types.StringType,
@@ -232,12 +250,12 @@ func (r *NetworkInterfaceResource) Create(ctx context.Context, req resource.Crea
 	}
 
 	if !data.DHCP.IsNull() {
-		handleEnableDHCP(r.hostData.Client, data.Name.ValueString(), data.DHCP.ValueString(), resp.Diagnostics)
+		handleEnableDHCP(r.hostData.Client, data.Name.ValueString(), data.DHCP.ValueString(), &resp.Diagnostics)
 	}
 	// adapters := r.read(ctx)
 	// Read Terraform prior state data into the model
 
-	adapters, _ := linuxhost_client.ReadAdapters(r.hostData)
+	adapters, _ := linuxhost_client.RefreshAdapters(r.hostData)
 	for _, s := range adapters {
 		tflog.Info(ctx, "Adding:"+s.Name+", mac: "+s.MAC)
 		if s.Name != data.Name.ValueString() {
@@ -272,6 +290,7 @@ func (r *NetworkInterfaceResource) Create(ctx context.Context, req resource.Crea
 			DHCP:            stringOrNull(s.DHCP),
 			VLAN_id:         numberOrNull(s.Vlan),
 		}
+		resp.Diagnostics.Append(dhcpLeaseToState(ctx, s.DHCPLease, N)...)
 		fmt.Println(N.Name.String())
 		resp.Diagnostics.Append(resp.State.Set(ctx, N)...)
 	}
@@ -323,6 +342,7 @@ func (r *NetworkInterfaceResource) Read(ctx context.Context, req resource.ReadRe
 			DHCP:            stringOrNull(s.DHCP),
 			VLAN_id:         numberOrNull(s.Vlan),
 		}
+		resp.Diagnostics.Append(dhcpLeaseToState(ctx, s.DHCPLease, N)...)
 		fmt.Println(N.Name.String())
 		resp.Diagnostics.Append(resp.State.Set(ctx, N)...)
 		return
@@ -334,7 +354,7 @@ func (r *NetworkInterfaceResource) Read(ctx context.Context, req resource.ReadRe
 	}
 }
 
-func handleEnableDHCP(Client *linuxhost_client.SSHClientContext, name string, dhcp string, diag diag.Diagnostics) {
+func handleEnableDHCP(Client *linuxhost_client.SSHClientContext, name string, dhcp string, diag *diag.Diagnostics) {
 	err := linuxhost_client.SetDhcp(Client, name, dhcp, true)
 	if err != nil {
 		diag.AddError("Failed to enable DHCP on interface", err.Error())
@@ -342,6 +362,23 @@ func handleEnableDHCP(Client *linuxhost_client.SSHClientContext, name string, dh
 
 }
 
+// dhcpLeaseToState sets the computed lease attributes, null without a lease
+func dhcpLeaseToState(ctx context.Context, lease *linuxhost_client.DhcpLease, data *models.NetworkInterfaceResourceModel) diag.Diagnostics {
+	if lease == nil {
+		lease = &linuxhost_client.DhcpLease{}
+	}
+	data.DhcpAddress = nonEmptyStringOrNull(lease.Address)
+	data.DhcpGateway = nonEmptyStringOrNull(lease.Gateway)
+	data.DhcpLeaseExpiry = nonEmptyStringOrNull(lease.Expiry)
+	if lease.DnsServers == nil {
+		data.DhcpDnsServers = types.ListNull(types.StringType)
+		return nil
+	}
+	var diags diag.Diagnostics
+	data.DhcpDnsServers, diags = types.ListValueFrom(ctx, types.StringType, lease.DnsServers)
+	return diags
+}
+
 func (r *NetworkInterfaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var desired models.NetworkInterfaceResourceModel
 	var state models.NetworkInterfaceResourceModel
@@ -362,15 +399,28 @@ func (r *NetworkInterfaceResource) Update(ctx context.Context, req resource.Upda
 	}
 
 	if !desired.DHCP.Equal(state.DHCP) {
-		if desired.DHCP.IsNull() {
+		// Switching clients stops the previous one first
+		if !state.DHCP.IsNull() {
 			err := linuxhost_client.SetDhcp(r.hostData.Client, desired.Name.ValueString(), state.DHCP.ValueString(), false)
 			if err != nil {
 				resp.Diagnostics.AddError("Failed to disable DHCP on interface", err.Error())
 			}
-		} else {
-			handleEnableDHCP(r.hostData.Client, desired.Name.ValueString(), desired.DHCP.ValueString(), resp.Diagnostics)
 		}
+		if !desired.DHCP.IsNull() && !resp.Diagnostics.HasError() {
+			handleEnableDHCP(r.hostData.Client, desired.Name.ValueString(), desired.DHCP.ValueString(), &resp.Diagnostics)
+		}
+	}
+
+	adapters, err := linuxhost_client.RefreshAdapters(r.hostData)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to read interfaces", err.Error())
+		return
+	}
+	var lease *linuxhost_client.DhcpLease
+	if adapter := adapters.GetByName(desired.Name.ValueString()); adapter != nil {
+		lease = adapter.DHCPLease
 	}
+	resp.Diagnostics.Append(dhcpLeaseToState(ctx, lease, &desired)...)
 
 	// If applicable, this is a great opportunity to initialize any necessary
 	// provider client data and make a call using it.
@@ -401,7 +451,7 @@ func (r *NetworkInterfaceResource) Delete(ctx context.Context, req resource.Dele
 }
 
 func (r *NetworkInterfaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("name"), req, resp)
 }
 
 var V0 = tftypes.Object{
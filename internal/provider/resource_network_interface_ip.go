@@ -3,19 +3,23 @@ package provider
 import (
 	"context"
 	"fmt"
+	"strings"
 	"terraform-provider-linuxhost/linuxhost_client"
 	models "terraform-provider-linuxhost/models"
 
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
 
 var _ resource.ResourceWithConfigure = &NetworkInterfaceIPResource{}
+var _ resource.ResourceWithImportState = &NetworkInterfaceIPResource{}
 
 func NewNetworkInterfaceIPResource() resource.Resource {
 	return &NetworkInterfaceIPResource{}
@@ -47,6 +51,7 @@ func (r *NetworkInterfaceIPResource) Schema(ctx context.Context, req resource.Sc
 					stringplanmodifier.RequiresReplace(),
 				},
 			},
+			"persistence": persistenceSchema("address"),
 		},
 		Version: 1,
 	}
@@ -75,6 +80,7 @@ func (r *NetworkInterfaceIPResource) Create(ctx context.Context, req resource.Cr
 			continue
 		}
 		resp.Diagnostics.Append(resp.State.Set(ctx, data)...)
+		resp.Diagnostics.Append(r.persist(ctx, &data, &resp.State, true)...)
 		return
 	}
 	resp.Diagnostics.AddError("Failed to read adapter", "didn't find adapter "+data.InterfaceName.String())
@@ -116,9 +122,11 @@ func (r *NetworkInterfaceIPResource) Read(ctx context.Context, req resource.Read
 			N := &models.NetowrkInterfaceIPAssignmentModel{
 				InterfaceName: types.StringValue(s.Name),
 				IPv4:          types.StringValue(ipv4),
+				Persistence:   data.Persistence,
 			}
 			fmt.Println(N.InterfaceName.String())
 			resp.Diagnostics.Append(resp.State.Set(ctx, N)...)
+			resp.Diagnostics.Append(r.persist(ctx, N, &resp.State, false)...)
 			return
 		}
 	}
@@ -132,18 +140,77 @@ func (r *NetworkInterfaceIPResource) Read(ctx context.Context, req resource.Read
 }
 
 func (r *NetworkInterfaceIPResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	var data models.NetowrkInterfaceIPAssignmentModel
+	var data, state models.NetowrkInterfaceIPAssignmentModel
 	// Read Terraform plan data into the model
 	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	resp.Diagnostics.AddError("Not implemented", "Update is not implemented.")
-	// return
+	// Only the persistence can change, everything else requires replacement
+	if r.persistenceName(&state) != r.persistenceName(&data) {
+		resp.Diagnostics.Append(r.removePersistence(&state)...)
+	}
 
 	// Save updated data into Terraform state
-	// resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+	resp.Diagnostics.Append(r.persist(ctx, &data, &resp.State, true)...)
+}
+
+// persist saves the address to its persistence backend, or with save false
+// verifies it and clears persistence in state if the saved file is outdated
+func (r *NetworkInterfaceIPResource) persist(ctx context.Context, data *models.NetowrkInterfaceIPAssignmentModel, state *tfsdk.State, save bool) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	backend, err := linuxhost_client.GetPersistence(r.hostData, r.persistenceName(data))
+	if err != nil {
+		diags.AddError("Invalid persistence for "+data.IPv4.ValueString(), err.Error())
+		return diags
+	}
+	if backend == nil {
+		return diags
+	}
+	dev := data.InterfaceName.ValueString()
+	address := data.IPv4.ValueString()
+	if save {
+		if err := backend.SaveAddress(r.hostData.Client, dev, address); err != nil {
+			diags.AddError("Failed persisting address "+address+" on "+dev, err.Error())
+		}
+		return diags
+	}
+	matches, err := backend.AddressMatches(r.hostData.Client, dev, address)
+	if err != nil {
+		diags.AddWarning("Failed verifying persisted address "+address+" on "+dev, err.Error())
+	}
+	if !matches {
+		if data.Persistence.IsNull() {
+			diags.AddWarning("Address "+address+" missing from the NetworkManager profile of "+dev, "It is added again on the next update.")
+		} else {
+			diags.Append(state.SetAttribute(ctx, path.Root("persistence"), types.StringNull())...)
+		}
+	}
+	return diags
+}
+
+// persistenceName is the persistence backend of the address, with the nmcli
+// network backend addresses are always added to the NetworkManager profile
+func (r *NetworkInterfaceIPResource) persistenceName(data *models.NetowrkInterfaceIPAssignmentModel) string {
+	if data.Persistence.IsNull() && r.hostData.NetworkBackend == "nmcli" {
+		return "nmcli"
+	}
+	return data.Persistence.ValueString()
+}
+
+func (r *NetworkInterfaceIPResource) removePersistence(data *models.NetowrkInterfaceIPAssignmentModel) diag.Diagnostics {
+	diags := diag.Diagnostics{}
+	backend, err := linuxhost_client.GetPersistence(r.hostData, r.persistenceName(data))
+	if err != nil || backend == nil {
+		return diags
+	}
+	if err := backend.RemoveAddress(r.hostData.Client, data.InterfaceName.ValueString(), data.IPv4.ValueString()); err != nil {
+		diags.AddWarning("Failed removing persisted address "+data.IPv4.ValueString(), err.Error())
+	}
+	return diags
 }
 
 func (r *NetworkInterfaceIPResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -151,6 +218,7 @@ func (r *NetworkInterfaceIPResource) Delete(ctx context.Context, req resource.De
 	// Read Terraform prior state data into the model
 	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
 
+	resp.Diagnostics.Append(r.removePersistence(&data)...)
 	err := linuxhost_client.DeleteIP(r.hostData.Client, data.InterfaceName.ValueString(), data.IPv4.ValueString())
 	if err != nil {
 		resp.Diagnostics.AddError("Failed to delete IP from interface", err.Error())
@@ -160,6 +228,15 @@ func (r *NetworkInterfaceIPResource) Delete(ctx context.Context, req resource.De
 		return
 	}
 }
+
+// ImportState imports the address by "<interface>/<address>", e.g.
+// eth0/192.0.2.10/24
 func (r *NetworkInterfaceIPResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	name, address, found := strings.Cut(req.ID, "/")
+	if !found || address == "" {
+		resp.Diagnostics.AddError("Invalid import id", "Expected <interface>/<address>, e.g. eth0/192.0.2.10/24, got "+req.ID)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("interface_name"), name)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("ipv4"), address)...)
 }
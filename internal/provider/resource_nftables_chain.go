@@ -0,0 +1,234 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int32planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &NftablesChainResource{}
+var _ resource.ResourceWithImportState = &NftablesChainResource{}
+var _ resource.ResourceWithValidateConfig = &NftablesChainResource{}
+
+func NewNftablesChainResource() resource.Resource {
+	return &NftablesChainResource{}
+}
+
+type NftablesChainResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *NftablesChainResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nftables_chain"
+}
+
+func (r *NftablesChainResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "An nftables chain. With `type` and `hook` it's a base chain seeing packets of the hook, otherwise a regular chain for jumps. Deleting it deletes its rules.",
+		Attributes: map[string]schema.Attribute{
+			"family": nftablesNameAttribute(nftablesFamilyDescription, stringvalidator.OneOf(linuxhost_client.NftFamilies...)),
+			"table":  nftablesNameAttribute("The table of the chain"),
+			"name":   nftablesNameAttribute("The name of the chain"),
+			"type": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The type of a base chain. Valid options: 'filter', 'nat', 'route'.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("filter", "nat", "route"),
+				},
+			},
+			"hook": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The hook of a base chain. Valid options: 'prerouting', 'input', 'forward', 'output', 'postrouting', 'ingress', 'egress'.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("prerouting", "input", "forward", "output", "postrouting", "ingress", "egress"),
+				},
+			},
+			"priority": schema.Int32Attribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The priority of a base chain among those of the hook, lower ones see packets first. Defaults to 0.",
+				PlanModifiers: []planmodifier.Int32{
+					int32planmodifier.UseStateForUnknown(),
+					int32planmodifier.RequiresReplace(),
+				},
+			},
+			"policy": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The verdict for packets no rule of a base chain decided on. Valid options: 'accept', 'drop'. Defaults to 'accept'.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("accept", "drop"),
+				},
+			},
+			"device": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The interface of a netdev ingress or egress base chain",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *NftablesChainResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *NftablesChainResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config models.NftablesChainModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.Type.IsNull() != config.Hook.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("hook"),
+			"Incomplete base chain",
+			"Base chains require both 'type' and 'hook'.",
+		)
+	}
+	if config.Type.IsNull() && (!config.Priority.IsNull() || !config.Policy.IsNull() || !config.Device.IsNull()) {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("type"),
+			"Unexpected attributes",
+			"Regular chains don't support 'priority', 'policy' or 'device'.",
+		)
+	}
+}
+
+// ImportState imports the chain by "<family>/<table>/<name>", e.g.
+// inet/filter/input
+func (r *NftablesChainResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	nftablesImportId(ctx, req.ID, []string{"family", "table", "name"}, resp)
+}
+
+func nftablesChainFromModel(data *models.NftablesChainModel) *linuxhost_client.NftChain {
+	chain := &linuxhost_client.NftChain{
+		Family: data.Family.ValueString(),
+		Table:  data.Table.ValueString(),
+		Name:   data.Name.ValueString(),
+		Type:   data.Type.ValueString(),
+		Hook:   data.Hook.ValueString(),
+		Policy: data.Policy.ValueString(),
+		Device: data.Device.ValueString(),
+	}
+	if !data.Priority.IsNull() && !data.Priority.IsUnknown() {
+		priority := data.Priority.ValueInt32()
+		chain.Priority = &priority
+	}
+	return chain
+}
+
+func (r *NftablesChainResource) readState(ctx context.Context, data *models.NftablesChainModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
+	ruleset, err := linuxhost_client.ReadNftRuleset(r.hostData.Client)
+	if err != nil {
+		Diagnostics.AddError("Failed reading nftables ruleset", err.Error())
+		return
+	}
+	chain := ruleset.GetChain(data.Family.ValueString(), data.Table.ValueString(), data.Name.ValueString())
+	if chain != nil {
+		if expect == "absent" {
+			Diagnostics.AddError("Failed to delete", "The delete operation did not report any errors but the resource remains present in the reported state.")
+			return
+		}
+		current := &models.NftablesChainModel{
+			Family:   types.StringValue(chain.Family),
+			Table:    types.StringValue(chain.Table),
+			Name:     types.StringValue(chain.Name),
+			Type:     nonEmptyStringOrNull(chain.Type),
+			Hook:     nonEmptyStringOrNull(chain.Hook),
+			Priority: types.Int32PointerValue(chain.Priority),
+			Policy:   nonEmptyStringOrNull(chain.Policy),
+			Device:   nonEmptyStringOrNull(chain.Device),
+		}
+		Diagnostics.Append(State.Set(ctx, current)...)
+		return
+	}
+	if expect == "present" {
+		Diagnostics.AddError("Didn't find nftables chain", "")
+	} else if expect == "any" || expect == "absent" {
+		State.RemoveResource(ctx)
+	} else {
+		Diagnostics.AddError("Invalid expectation", "This is an error with the provider 'linuxhost'")
+	}
+}
+
+func (r *NftablesChainResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.NftablesChainModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetNftChain(r.hostData.Client, nftablesChainFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed creating nftables chain", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *NftablesChainResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.NftablesChainModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "any")
+}
+
+// Update changes the policy, all other attributes require a replacement
+func (r *NftablesChainResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.NftablesChainModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetNftChain(r.hostData.Client, nftablesChainFromModel(&plan)); err != nil {
+		resp.Diagnostics.AddError("Failed updating nftables chain", err.Error())
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *NftablesChainResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.NftablesChainModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.DeleteNftChain(r.hostData.Client, nftablesChainFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed to delete nftables chain", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "absent")
+}
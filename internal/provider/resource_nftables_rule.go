@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &NftablesRuleResource{}
+
+func NewNftablesRuleResource() resource.Resource {
+	return &NftablesRuleResource{}
+}
+
+type NftablesRuleResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+// nftablesRuleExprKey is the private state key of the expression nft listed
+// after the rule was applied, the rule text can't be compared as nft
+// normalizes it
+const nftablesRuleExprKey = "expr"
+
+// privateState is the private state of both requests and responses
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+	SetKey(ctx context.Context, key string, value []byte) diag.Diagnostics
+}
+
+func (r *NftablesRuleResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nftables_rule"
+}
+
+func (r *NftablesRuleResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "An nftables rule. The rule is identified by a comment the provider adds, so rules of other tools and manual changes of the chain don't confuse it.",
+		Attributes: map[string]schema.Attribute{
+			"family": nftablesNameAttribute(nftablesFamilyDescription, stringvalidator.OneOf(linuxhost_client.NftFamilies...)),
+			"table":  nftablesNameAttribute("The table of the rule"),
+			"chain":  nftablesNameAttribute("The chain of the rule"),
+			"rule": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The rule in the nft syntax without a comment, e.g. 'tcp dport 22 accept'. Changes replace the rule in place.",
+			},
+			"insert": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Whether the rule is inserted at the start of the chain rather than appended",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.RequiresReplace(),
+				},
+			},
+			"comment": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The comment identifying the rule",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"handle": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The handle of the rule in its chain",
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *NftablesRuleResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+// readState finds the rule by its comment. After an apply its expression is
+// recorded, otherwise it's compared to the recorded one and a changed rule
+// reports its current text.
+func (r *NftablesRuleResource) readState(ctx context.Context, data *models.NftablesRuleModel, State *tfsdk.State, Private privateState, Diagnostics *diag.Diagnostics, applied bool) {
+	ruleset, err := linuxhost_client.ReadNftRuleset(r.hostData.Client)
+	if err != nil {
+		Diagnostics.AddError("Failed reading nftables ruleset", err.Error())
+		return
+	}
+	rule := ruleset.GetRule(data.Family.ValueString(), data.Table.ValueString(), data.Comment.ValueString())
+	if rule == nil {
+		if applied {
+			Diagnostics.AddError("Didn't find nftables rule", "")
+		} else {
+			State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	current := *data
+	current.Chain = types.StringValue(rule.Chain)
+	current.Handle = types.Int64Value(rule.Handle)
+	if applied {
+		Diagnostics.Append(Private.SetKey(ctx, nftablesRuleExprKey, rule.Expr)...)
+	} else {
+		expr, diags := Private.GetKey(ctx, nftablesRuleExprKey)
+		Diagnostics.Append(diags...)
+		if expr != nil && !linuxhost_client.NftExprEqual(expr, rule.Expr) {
+			text, err := linuxhost_client.ReadNftRuleText(r.hostData.Client, rule.Family, rule.Table, rule.Chain, rule.Handle)
+			if err != nil {
+				Diagnostics.AddError("Failed reading nftables rule", err.Error())
+				return
+			}
+			current.Rule = types.StringValue(text)
+		}
+	}
+	Diagnostics.Append(State.Set(ctx, &current)...)
+}
+
+func (r *NftablesRuleResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.NftablesRuleModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	comment, err := linuxhost_client.NewRuleComment()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed generating rule comment", err.Error())
+		return
+	}
+	data.Comment = types.StringValue(comment)
+	if err := linuxhost_client.AddNftRule(r.hostData.Client, data.Family.ValueString(), data.Table.ValueString(), data.Chain.ValueString(), data.Rule.ValueString(), comment, data.Insert.ValueBool()); err != nil {
+		resp.Diagnostics.AddError("Failed creating nftables rule", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, resp.Private, &resp.Diagnostics, true)
+}
+
+func (r *NftablesRuleResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.NftablesRuleModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, req.Private, &resp.Diagnostics, false)
+}
+
+// Update replaces the rule by its handle, keeping its position in the chain
+func (r *NftablesRuleResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.NftablesRuleModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.ReplaceNftRule(r.hostData.Client, plan.Family.ValueString(), plan.Table.ValueString(), plan.Chain.ValueString(), plan.Handle.ValueInt64(), plan.Rule.ValueString(), plan.Comment.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed updating nftables rule", err.Error())
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, resp.Private, &resp.Diagnostics, true)
+}
+
+func (r *NftablesRuleResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.NftablesRuleModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The rule is gone with its chain or table
+	ruleset, err := linuxhost_client.ReadNftRuleset(r.hostData.Client)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed reading nftables ruleset", err.Error())
+		return
+	}
+	rule := ruleset.GetRule(data.Family.ValueString(), data.Table.ValueString(), data.Comment.ValueString())
+	if rule == nil {
+		return
+	}
+	if err := linuxhost_client.DeleteNftRule(r.hostData.Client, rule.Family, rule.Table, rule.Chain, rule.Handle); err != nil {
+		resp.Diagnostics.AddError("Failed to delete nftables rule", err.Error())
+	}
+}
@@ -0,0 +1,162 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &NftablesTableResource{}
+var _ resource.ResourceWithImportState = &NftablesTableResource{}
+
+func NewNftablesTableResource() resource.Resource {
+	return &NftablesTableResource{}
+}
+
+type NftablesTableResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *NftablesTableResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_nftables_table"
+}
+
+// nftablesNameAttribute is a required name of a table, chain or the family,
+// all of which can't change
+func nftablesNameAttribute(description string, validators ...validator.String) schema.StringAttribute {
+	return schema.StringAttribute{
+		Required:            true,
+		MarkdownDescription: description,
+		PlanModifiers: []planmodifier.String{
+			stringplanmodifier.RequiresReplace(),
+		},
+		Validators: validators,
+	}
+}
+
+var nftablesFamilyDescription = "The address family: '" + strings.Join(linuxhost_client.NftFamilies, "', '") + "'"
+
+// nftablesImportId sets the attributes named by parts from an import id of
+// values separated by '/'
+func nftablesImportId(ctx context.Context, id string, parts []string, resp *resource.ImportStateResponse) {
+	values := strings.Split(id, "/")
+	if len(values) != len(parts) {
+		resp.Diagnostics.AddError("Invalid import id", "Expected <"+strings.Join(parts, ">/<")+">, got "+id)
+		return
+	}
+	for i := range parts {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(parts[i]), values[i])...)
+	}
+}
+
+func (r *NftablesTableResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "An nftables table. Deleting it deletes its chains and rules.",
+		Attributes: map[string]schema.Attribute{
+			"family": nftablesNameAttribute(nftablesFamilyDescription, stringvalidator.OneOf(linuxhost_client.NftFamilies...)),
+			"name":   nftablesNameAttribute("The name of the table"),
+		},
+		Version: 1,
+	}
+}
+
+func (r *NftablesTableResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+// ImportState imports the table by "<family>/<name>", e.g. inet/filter
+func (r *NftablesTableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	nftablesImportId(ctx, req.ID, []string{"family", "name"}, resp)
+}
+
+func (r *NftablesTableResource) readState(ctx context.Context, data *models.NftablesTableModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
+	ruleset, err := linuxhost_client.ReadNftRuleset(r.hostData.Client)
+	if err != nil {
+		Diagnostics.AddError("Failed reading nftables ruleset", err.Error())
+		return
+	}
+	table := ruleset.GetTable(data.Family.ValueString(), data.Name.ValueString())
+	if table != nil {
+		if expect == "absent" {
+			Diagnostics.AddError("Failed to delete", "The delete operation did not report any errors but the resource remains present in the reported state.")
+			return
+		}
+		current := &models.NftablesTableModel{
+			Family: types.StringValue(table.Family),
+			Name:   types.StringValue(table.Name),
+		}
+		Diagnostics.Append(State.Set(ctx, current)...)
+		return
+	}
+	if expect == "present" {
+		Diagnostics.AddError("Didn't find nftables table", "")
+	} else if expect == "any" || expect == "absent" {
+		State.RemoveResource(ctx)
+	} else {
+		Diagnostics.AddError("Invalid expectation", "This is an error with the provider 'linuxhost'")
+	}
+}
+
+func (r *NftablesTableResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.NftablesTableModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.CreateNftTable(r.hostData.Client, data.Family.ValueString(), data.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed creating nftables table", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *NftablesTableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.NftablesTableModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "any")
+}
+
+// Update is never called, all attributes require a replacement
+func (r *NftablesTableResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.NftablesTableModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *NftablesTableResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.NftablesTableModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.DeleteNftTable(r.hostData.Client, data.Family.ValueString(), data.Name.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete nftables table", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "absent")
+}
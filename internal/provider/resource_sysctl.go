@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &SysctlResource{}
+var _ resource.ResourceWithImportState = &SysctlResource{}
+
+func NewSysctlResource() resource.Resource {
+	return &SysctlResource{}
+}
+
+type SysctlResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+// sysctlKeyValidator accepts keys with dots or slashes as separators, the
+// latter for interface names containing dots
+var sysctlKeyValidator = stringvalidator.RegexMatches(regexp.MustCompile(`^[A-Za-z0-9_.@:/-]+$`), "must only contain letters, digits, '.', '/', '_', '-', '@' and ':'")
+
+func (r *SysctlResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_sysctl"
+}
+
+func (r *SysctlResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A kernel parameter set with `sysctl -w`, optionally persisted to a file below /etc/sysctl.d. Deleting the resource removes the file but leaves the runtime value.",
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The parameter, e.g. 'net.ipv4.ip_forward'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					sysctlKeyValidator,
+				},
+			},
+			"value": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The value. Multiple fields like those of 'net.ipv4.ip_local_port_range' are separated by spaces.",
+			},
+			"persist": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Whether the value is written to a provider-managed file in /etc/sysctl.d to survive reboots",
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *SysctlResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *SysctlResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	resource.ImportStatePassthroughID(ctx, path.Root("key"), req, resp)
+}
+
+// readState reports the runtime value, and persist only if the sysctl.d file
+// holds the value of data. An imported resource has no value yet and takes
+// whatever the file holds.
+func (r *SysctlResource) readState(ctx context.Context, data *models.SysctlModel, State *tfsdk.State, Diagnostics *diag.Diagnostics, expect string) {
+	key := data.Key.ValueString()
+	value, err := linuxhost_client.ReadSysctl(r.hostData.Client, key)
+	if err != nil {
+		Diagnostics.AddError("Failed reading sysctl", err.Error())
+		return
+	}
+	if value == nil {
+		if expect == "present" {
+			Diagnostics.AddError("Didn't find sysctl", fmt.Sprintf("The kernel parameter %s does not exist.", key))
+		} else {
+			State.RemoveResource(ctx)
+		}
+		return
+	}
+	persisted, err := linuxhost_client.ReadPersistedSysctl(r.hostData.Client, key)
+	if err != nil {
+		Diagnostics.AddError("Failed reading persisted sysctl", err.Error())
+		return
+	}
+
+	current := &models.SysctlModel{
+		Key:     data.Key,
+		Value:   types.StringValue(*value),
+		Persist: types.BoolValue(persisted != nil),
+	}
+	desired := linuxhost_client.NormalizeSysctlValue(data.Value.ValueString())
+	if expect == "present" && desired != *value {
+		Diagnostics.AddError("Value not applied", fmt.Sprintf("sysctl reported no error but %s is %q instead of %q.", key, *value, desired))
+		return
+	}
+	if !data.Value.IsNull() {
+		// Keep the configured spelling of an equal value
+		if desired == *value {
+			current.Value = data.Value
+		}
+		current.Persist = types.BoolValue(persisted != nil && *persisted == desired)
+	}
+	Diagnostics.Append(State.Set(ctx, current)...)
+}
+
+func (r *SysctlResource) apply(data *models.SysctlModel, Diagnostics *diag.Diagnostics) {
+	key := data.Key.ValueString()
+	value := data.Value.ValueString()
+	if err := linuxhost_client.SetSysctl(r.hostData.Client, key, value); err != nil {
+		Diagnostics.AddError("Failed setting sysctl", err.Error())
+		return
+	}
+	var err error
+	if data.Persist.ValueBool() {
+		err = linuxhost_client.PersistSysctl(r.hostData.Client, key, linuxhost_client.NormalizeSysctlValue(value))
+	} else {
+		err = linuxhost_client.UnpersistSysctl(r.hostData.Client, key)
+	}
+	if err != nil {
+		Diagnostics.AddError("Failed persisting sysctl", err.Error())
+	}
+}
+
+func (r *SysctlResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.SysctlModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(&data, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *SysctlResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.SysctlModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, &resp.Diagnostics, "any")
+}
+
+func (r *SysctlResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.SysctlModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.apply(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, &resp.Diagnostics, "present")
+}
+
+func (r *SysctlResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.SysctlModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.UnpersistSysctl(r.hostData.Client, data.Key.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to remove persisted sysctl", err.Error())
+	}
+}
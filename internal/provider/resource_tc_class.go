@@ -0,0 +1,225 @@
+package provider
+
+import (
+	"context"
+	"strings"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &TcClassResource{}
+var _ resource.ResourceWithImportState = &TcClassResource{}
+
+func NewTcClassResource() resource.Resource {
+	return &TcClassResource{}
+}
+
+type TcClassResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *TcClassResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tc_class"
+}
+
+func (r *TcClassResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A traffic control class of a classful qdisc like htb",
+		Attributes: map[string]schema.Attribute{
+			"dev": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The interface of the class, e.g. 'eth0'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"parent": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The qdisc like '1:' or the class like '1:1' the class belongs to. Defaults to the qdisc of `classid`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(tcClassIdRegex, "must be a qdisc like '1:' or a class like '1:1'"),
+				},
+			},
+			"classid": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The id of the class like '1:10', its major number is the handle of the qdisc",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(tcClassIdRegex, "must be a class like '1:10'"),
+				},
+			},
+			"kind": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The kind of the class, the kind of its qdisc like 'htb'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"options": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The parameters of the kind in tc syntax, e.g. 'rate 100mbit ceil 1gbit' for htb",
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *TcClassResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+// ImportState imports the class by "<dev>/<classid>", e.g. eth0/1:10
+func (r *TcClassResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	dev, classId, found := strings.Cut(req.ID, "/")
+	if !found || classId == "" {
+		resp.Diagnostics.AddError("Invalid import id", "Expected <dev>/<classid>, e.g. eth0/1:10, got "+req.ID)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dev"), dev)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("classid"), classId)...)
+}
+
+// tcClassQdisc is the handle of the qdisc of a class
+func tcClassQdisc(classId string) string {
+	major, _, _ := strings.Cut(classId, ":")
+	return major + ":"
+}
+
+func tcClassFromModel(data *models.TcClassModel) *linuxhost_client.TcClass {
+	class := &linuxhost_client.TcClass{
+		Dev:     data.Dev.ValueString(),
+		Parent:  data.Parent.ValueString(),
+		ClassId: data.ClassId.ValueString(),
+		Kind:    data.Kind.ValueString(),
+		Options: data.Options.ValueString(),
+	}
+	if data.Parent.IsUnknown() || data.Parent.IsNull() {
+		class.Parent = tcClassQdisc(class.ClassId)
+	}
+	return class
+}
+
+// readState finds the class by its id. After an apply it's recorded,
+// otherwise it's compared to the recorded one and changed options are
+// reported as tc prints them.
+func (r *TcClassResource) readState(ctx context.Context, data *models.TcClassModel, State *tfsdk.State, Private privateState, Diagnostics *diag.Diagnostics, applied bool) {
+	dev := data.Dev.ValueString()
+	classes, err := linuxhost_client.ReadTcObjects(r.hostData.Client, "class", dev)
+	if err != nil {
+		Diagnostics.AddError("Failed reading classes", err.Error())
+		return
+	}
+	class := linuxhost_client.FindTcClass(classes, data.ClassId.ValueString())
+	if class == nil {
+		if applied {
+			Diagnostics.AddError("Didn't find class", "")
+		} else {
+			State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	current := *data
+	current.Kind = types.StringValue(class.String("class"))
+	// Classes directly below the qdisc are listed as root
+	current.Parent = types.StringValue(tcClassQdisc(data.ClassId.ValueString()))
+	if parent := class.String("parent"); parent != "" {
+		current.Parent = types.StringValue(parent)
+	}
+	if applied {
+		Diagnostics.Append(Private.SetKey(ctx, tcFingerprintKey, class.Fingerprint())...)
+	} else {
+		fingerprint, diags := Private.GetKey(ctx, tcFingerprintKey)
+		Diagnostics.Append(diags...)
+		if fingerprint != nil && string(fingerprint) != string(class.Fingerprint()) {
+			options, err := linuxhost_client.ReadTcOptions(r.hostData.Client, "class", dev, data.ClassId.ValueString())
+			if err != nil {
+				Diagnostics.AddError("Failed reading class", err.Error())
+				return
+			}
+			current.Options = types.StringValue(options)
+		}
+	}
+	Diagnostics.Append(State.Set(ctx, &current)...)
+}
+
+func (r *TcClassResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.TcClassModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetTcClass(r.hostData.Client, tcClassFromModel(&data), false); err != nil {
+		resp.Diagnostics.AddError("Failed creating class", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, resp.Private, &resp.Diagnostics, true)
+}
+
+func (r *TcClassResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.TcClassModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, req.Private, &resp.Diagnostics, false)
+}
+
+func (r *TcClassResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.TcClassModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetTcClass(r.hostData.Client, tcClassFromModel(&plan), true); err != nil {
+		resp.Diagnostics.AddError("Failed updating class", err.Error())
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, resp.Private, &resp.Diagnostics, true)
+}
+
+func (r *TcClassResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.TcClassModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The class is gone with its interface or qdisc
+	classes, err := linuxhost_client.ReadTcObjects(r.hostData.Client, "class", data.Dev.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed reading classes", err.Error())
+		return
+	}
+	if linuxhost_client.FindTcClass(classes, data.ClassId.ValueString()) == nil {
+		return
+	}
+	if err := linuxhost_client.DeleteTcClass(r.hostData.Client, data.Dev.ValueString(), data.ClassId.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete class", err.Error())
+	}
+}
@@ -0,0 +1,256 @@
+package provider
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var _ resource.ResourceWithConfigure = &TcQdiscResource{}
+var _ resource.ResourceWithImportState = &TcQdiscResource{}
+var _ resource.ResourceWithValidateConfig = &TcQdiscResource{}
+
+func NewTcQdiscResource() resource.Resource {
+	return &TcQdiscResource{}
+}
+
+type TcQdiscResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+// tcFingerprintKey is the private state key of the qdisc or class as listed
+// after it was applied, the options can't be compared as tc normalizes them
+const tcFingerprintKey = "tc"
+
+var tcClassIdRegex = regexp.MustCompile(`^[0-9a-f]+:[0-9a-f]*$`)
+
+func (r *TcQdiscResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_tc_qdisc"
+}
+
+func (r *TcQdiscResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A traffic control queueing discipline. Deleting a root qdisc restores the default one of the interface.",
+		Attributes: map[string]schema.Attribute{
+			"dev": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The interface of the qdisc, e.g. 'eth0'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"parent": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("root"),
+				MarkdownDescription: "Where the qdisc is attached: 'root', 'ingress', 'clsact' or a class like '1:10'. Defaults to 'root'.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.Any(
+						stringvalidator.OneOf("root", "ingress", "clsact"),
+						stringvalidator.RegexMatches(tcClassIdRegex, "must be a class like '1:10'"),
+					),
+				},
+			},
+			"handle": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The handle of the qdisc like '1:', classes of the qdisc use its major number. Assigned by the kernel if unspecified.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-f]+:$`), "must be a major number followed by ':' like '1:'"),
+				},
+			},
+			"kind": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "The kind of the qdisc, e.g. 'fq_codel', 'htb', 'tbf' or 'netem'. Required unless `parent` is 'ingress' or 'clsact', which are their own kind.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"options": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The parameters of the kind in tc syntax, e.g. 'delay 100ms 10ms loss 1%' for netem",
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *TcQdiscResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func (r *TcQdiscResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config models.TcQdiscModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if config.Parent.IsUnknown() || config.Kind.IsUnknown() {
+		return
+	}
+	parent := config.Parent.ValueString()
+	if parent == "ingress" || parent == "clsact" {
+		if !config.Kind.IsNull() && config.Kind.ValueString() != parent {
+			resp.Diagnostics.AddAttributeError(path.Root("kind"), "Unexpected kind", "The qdisc at '"+parent+"' is of kind '"+parent+"'.")
+		}
+		if !config.Handle.IsNull() {
+			resp.Diagnostics.AddAttributeError(path.Root("handle"), "Unexpected handle", "The handle of the qdisc at '"+parent+"' is fixed.")
+		}
+	} else if config.Kind.IsNull() {
+		resp.Diagnostics.AddAttributeError(path.Root("kind"), "Missing kind", "The 'kind' attribute is required unless 'parent' is 'ingress' or 'clsact'.")
+	}
+}
+
+// ImportState imports the qdisc by "<dev>/<parent>", e.g. eth0/root
+func (r *TcQdiscResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	dev, parent, found := strings.Cut(req.ID, "/")
+	if !found || parent == "" {
+		resp.Diagnostics.AddError("Invalid import id", "Expected <dev>/<parent>, e.g. eth0/root, got "+req.ID)
+		return
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("dev"), dev)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("parent"), parent)...)
+}
+
+func tcQdiscFromModel(data *models.TcQdiscModel) *linuxhost_client.TcQdisc {
+	qdisc := &linuxhost_client.TcQdisc{
+		Dev:     data.Dev.ValueString(),
+		Parent:  data.Parent.ValueString(),
+		Kind:    data.Kind.ValueString(),
+		Options: data.Options.ValueString(),
+	}
+	if !data.Handle.IsUnknown() {
+		qdisc.Handle = data.Handle.ValueString()
+	}
+	return qdisc
+}
+
+// readState finds the qdisc at its parent. After an apply it's recorded,
+// otherwise it's compared to the recorded one and changed options are
+// reported as tc prints them.
+func (r *TcQdiscResource) readState(ctx context.Context, data *models.TcQdiscModel, State *tfsdk.State, Private privateState, Diagnostics *diag.Diagnostics, applied bool) {
+	dev := data.Dev.ValueString()
+	qdiscs, err := linuxhost_client.ReadTcObjects(r.hostData.Client, "qdisc", dev)
+	if err != nil {
+		Diagnostics.AddError("Failed reading qdiscs", err.Error())
+		return
+	}
+	qdisc := linuxhost_client.FindTcQdisc(qdiscs, data.Parent.ValueString())
+	if qdisc == nil {
+		if applied {
+			Diagnostics.AddError("Didn't find qdisc", "")
+		} else {
+			State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	current := *data
+	current.Kind = types.StringValue(qdisc.String("kind"))
+	current.Handle = types.StringValue(qdisc.String("handle"))
+	if applied {
+		Diagnostics.Append(Private.SetKey(ctx, tcFingerprintKey, qdisc.Fingerprint())...)
+	} else {
+		fingerprint, diags := Private.GetKey(ctx, tcFingerprintKey)
+		Diagnostics.Append(diags...)
+		if fingerprint != nil && string(fingerprint) != string(qdisc.Fingerprint()) {
+			options, err := linuxhost_client.ReadTcOptions(r.hostData.Client, "qdisc", dev, qdisc.String("handle"))
+			if err != nil {
+				Diagnostics.AddError("Failed reading qdisc", err.Error())
+				return
+			}
+			current.Options = types.StringValue(options)
+		}
+	}
+	Diagnostics.Append(State.Set(ctx, &current)...)
+}
+
+func (r *TcQdiscResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.TcQdiscModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Kind.IsUnknown() {
+		data.Kind = data.Parent
+	}
+	if err := linuxhost_client.SetTcQdisc(r.hostData.Client, tcQdiscFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed creating qdisc", err.Error())
+		return
+	}
+	r.readState(ctx, &data, &resp.State, resp.Private, &resp.Diagnostics, true)
+}
+
+func (r *TcQdiscResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.TcQdiscModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.readState(ctx, &data, &resp.State, req.Private, &resp.Diagnostics, false)
+}
+
+// Update replaces the options of the qdisc, keeping its handle
+func (r *TcQdiscResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.TcQdiscModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetTcQdisc(r.hostData.Client, tcQdiscFromModel(&plan)); err != nil {
+		resp.Diagnostics.AddError("Failed updating qdisc", err.Error())
+		return
+	}
+	r.readState(ctx, &plan, &resp.State, resp.Private, &resp.Diagnostics, true)
+}
+
+func (r *TcQdiscResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.TcQdiscModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The qdisc is gone with its interface or parent class, or was replaced
+	qdiscs, err := linuxhost_client.ReadTcObjects(r.hostData.Client, "qdisc", data.Dev.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed reading qdiscs", err.Error())
+		return
+	}
+	if qdisc := linuxhost_client.FindTcQdisc(qdiscs, data.Parent.ValueString()); qdisc == nil || qdisc.String("handle") != data.Handle.ValueString() {
+		return
+	}
+	if err := linuxhost_client.DeleteTcQdisc(r.hostData.Client, data.Dev.ValueString(), data.Parent.ValueString()); err != nil {
+		resp.Diagnostics.AddError("Failed to delete qdisc", err.Error())
+	}
+}
@@ -22,6 +22,7 @@ import (
 )
 
 var _ resource.ResourceWithConfigure = &UserResource{}
+var _ resource.ResourceWithImportState = &UserResource{}
 var _ resource.ResourceWithConfigValidators = &UserResource{}
 var _ resource.ResourceWithModifyPlan = &UserResource{}
 
@@ -261,7 +262,7 @@ func (r *UserResource) Delete(ctx context.Context, req resource.DeleteRequest, r
 	}
 }
 func (r *UserResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
-	resource.ImportStatePassthroughID(ctx, path.Root("id"), req, resp)
+	resource.ImportStatePassthroughID(ctx, path.Root("username"), req, resp)
 }
 
 func (r *UserResource) ConfigValidators(ctx context.Context) []resource.ConfigValidator {
@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"terraform-provider-linuxhost/linuxhost_client"
+	models "terraform-provider-linuxhost/models"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int32validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var _ resource.ResourceWithConfigure = &WireguardPeerResource{}
+
+func NewWireguardPeerResource() resource.Resource {
+	return &WireguardPeerResource{}
+}
+
+type WireguardPeerResource struct {
+	hostData *linuxhost_client.HostData
+}
+
+func (r *WireguardPeerResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_wireguard_peer"
+}
+
+func (r *WireguardPeerResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A peer of a WireGuard interface",
+		Attributes: map[string]schema.Attribute{
+			"interface": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The WireGuard interface the peer belongs to, e.g. 'wg0'",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"netns": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "If specified, the network namespace of the interface, either a name as created by `linuxhost_netns` or the PID of a process inside the namespace.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"public_key": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "The base64 public key of the peer",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"preshared_key": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: "An optional base64 preshared key for an additional layer of symmetric encryption",
+			},
+			"allowed_ips": schema.SetAttribute{
+				ElementType:         types.StringType,
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.Set{setplanmodifier.UseStateForUnknown()},
+				MarkdownDescription: "The networks routed to this peer, e.g. '10.0.0.2/32'",
+			},
+			"endpoint": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				PlanModifiers:       []planmodifier.String{stringplanmodifier.UseStateForUnknown()},
+				MarkdownDescription: "The peer's address as 'ip:port'. If unspecified, this field contains the endpoint learned from the peer.",
+			},
+			"persistent_keepalive": schema.Int32Attribute{
+				Optional:            true,
+				MarkdownDescription: "Interval in seconds between keepalive packets, unset disables it.",
+				Validators: []validator.Int32{
+					int32validator.Between(1, 65535),
+				},
+			},
+		},
+		Version: 1,
+	}
+}
+
+func (r *WireguardPeerResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.hostData, _ = req.ProviderData.(*linuxhost_client.HostData)
+}
+
+func wireguardPeerFromModel(data *models.WireguardPeerModel) *linuxhost_client.WireguardPeer {
+	allowedIPs, _ := linuxhost_client.ValuesToStrings(data.AllowedIPs.Elements())
+	return &linuxhost_client.WireguardPeer{
+		Interface:           data.Interface.ValueString(),
+		Netns:               data.Netns.ValueString(),
+		PublicKey:           data.PublicKey.ValueString(),
+		PresharedKey:        data.PresharedKey.ValueString(),
+		Endpoint:            data.Endpoint.ValueString(),
+		AllowedIPs:          allowedIPs,
+		PersistentKeepalive: uint32(data.PersistentKeepalive.ValueInt32()),
+	}
+}
+
+func (r *WireguardPeerResource) makeStateRefresher(ctx context.Context, data *models.WireguardPeerModel, State *tfsdk.State, Diagnostics *diag.Diagnostics) *ReadableResource[models.WireguardPeerModel] {
+	tflog.Debug(ctx, "Refreshing WireGuard peers")
+	currentState := []models.WireguardPeerModel{}
+	dump, err := linuxhost_client.ReadWireguardInNetns(r.hostData.Client, data.Netns.ValueString(), data.Interface.ValueString())
+	if err != nil {
+		tflog.Warn(ctx, "Failed to read WireGuard interface "+data.Interface.ValueString()+": "+err.Error())
+	}
+	if dump != nil {
+		for _, peer := range dump.Peers {
+			allowedIPs, _ := types.SetValueFrom(ctx, types.StringType, peer.AllowedIPs)
+			keepalive := types.Int32Null()
+			if peer.PersistentKeepalive != 0 {
+				keepalive = int32OrNull(peer.PersistentKeepalive)
+			}
+			currentState = append(currentState, models.WireguardPeerModel{
+				Interface:           data.Interface,
+				Netns:               data.Netns,
+				PublicKey:           types.StringValue(peer.PublicKey),
+				PresharedKey:        stringOrNull(peer.PresharedKey),
+				AllowedIPs:          allowedIPs,
+				Endpoint:            stringOrNull(peer.Endpoint),
+				PersistentKeepalive: keepalive,
+			})
+		}
+	}
+	return &ReadableResource[models.WireguardPeerModel]{
+		Ctx:          ctx,
+		State:        State,
+		Diagnostics:  Diagnostics,
+		currentState: currentState,
+		Equal: func(A, B *models.WireguardPeerModel) bool {
+			return A.Interface.Equal(B.Interface) && A.PublicKey.Equal(B.PublicKey)
+		},
+		New: func(current, target *models.WireguardPeerModel) models.WireguardPeerModel { return *current },
+	}
+}
+
+func (r *WireguardPeerResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var data models.WireguardPeerModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetWireguardPeer(r.hostData.Client, wireguardPeerFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed creating WireGuard peer", err.Error())
+		return
+	}
+
+	r.makeStateRefresher(ctx, &data, &resp.State, &resp.Diagnostics).InState(data, "present")
+}
+
+func (r *WireguardPeerResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	if r.hostData == nil {
+		resp.Diagnostics.AddError("Missing client", "")
+		return
+	}
+	var data models.WireguardPeerModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	r.makeStateRefresher(ctx, &data, &resp.State, &resp.Diagnostics).InState(data, "any")
+}
+
+func (r *WireguardPeerResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan models.WireguardPeerModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.SetWireguardPeer(r.hostData.Client, wireguardPeerFromModel(&plan)); err != nil {
+		resp.Diagnostics.AddError("Failed updating WireGuard peer", err.Error())
+		return
+	}
+
+	r.makeStateRefresher(ctx, &plan, &resp.State, &resp.Diagnostics).InState(plan, "present")
+}
+
+func (r *WireguardPeerResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var data models.WireguardPeerModel
+	// Read Terraform prior state data into the model
+	resp.Diagnostics.Append(req.State.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := linuxhost_client.DeleteWireguardPeer(r.hostData.Client, wireguardPeerFromModel(&data)); err != nil {
+		resp.Diagnostics.AddError("Failed to delete WireGuard peer", err.Error())
+		return
+	}
+	r.makeStateRefresher(ctx, &data, &resp.State, &resp.Diagnostics).InState(data, "absent")
+}
@@ -2,6 +2,8 @@ package linuxhost_client
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -23,6 +25,27 @@ func (c SSHClientConfiguration) Address() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// hostLocks hands out a mutex per host, so edits of a shared file are
+// serialized on one host without making the other hosts wait
+type hostLocks struct {
+	mu    sync.Mutex
+	locks map[string]*sync.Mutex
+}
+
+// of returns the mutex of the host connectedClient is connected to
+func (l *hostLocks) of(connectedClient *SSHClientContext) *sync.Mutex {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.locks == nil {
+		l.locks = map[string]*sync.Mutex{}
+	}
+	address := connectedClient.Configuration.Address()
+	if l.locks[address] == nil {
+		l.locks[address] = &sync.Mutex{}
+	}
+	return l.locks[address]
+}
+
 // NewSSHClient creates a new SSHClient with the given parameters.
 func NewSSHClient(host string, port int64, username, password, privateKey string) (*SSHClientContext, error) {
 	sshConfig := &ssh.ClientConfig{
@@ -64,11 +87,20 @@ func NewSSHClient(host string, port int64, username, password, privateKey string
 
 // ExecuteCommand runs a command on the remote host and returns the output.
 func (c *SSHClientContext) ExecuteCommand(cmd string) (string, error) {
+	return c.ExecuteCommandWithInput(cmd, "")
+}
+
+// ExecuteCommandWithInput runs a command with input on its stdin. Secrets are
+// passed this way, unlike the command line they don't show up in `ps`.
+func (c *SSHClientContext) ExecuteCommandWithInput(cmd string, input string) (string, error) {
 	session, err := c.Client.NewSession()
 	if err != nil {
 		return "", fmt.Errorf("failed to create SSH session: %w", err)
 	}
 	defer session.Close()
+	if input != "" {
+		session.Stdin = strings.NewReader(input)
+	}
 
 	output, err := session.CombinedOutput(cmd)
 	var result string
@@ -18,32 +18,66 @@ type CertificateData struct {
 }
 
 func CertificateInfo(certificatePath string) CertificateData {
+	data, err := ReadCertificateInfo(certificatePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return data
+}
+
+// ReadCertificateInfo is CertificateInfo returning an error instead of exiting
+func ReadCertificateInfo(certificatePath string) (CertificateData, error) {
 	/// Load the certificate (PEM format)
 	certPEM, err := os.ReadFile(certificatePath)
 	if err != nil {
-		log.Fatalf("Error reading certificate file (%s): %v", certificatePath, err)
+		return CertificateData{}, fmt.Errorf("error reading certificate file (%s): %v", certificatePath, err)
+	}
+	cert, err := parseCertificatePEM(certPEM)
+	if err != nil {
+		return CertificateData{}, err
 	}
 
+	/// Compute the fingerprint
+	subject := cert.Subject.ToRDNSequence().String()
+	hash := sha256.Sum256(cert.Raw)
+
+	return CertificateData{
+		Subject:           subject,
+		Sha256Fingerprint: hash,
+	}, nil
+}
+
+func parseCertificatePEM(certPEM []byte) (*x509.Certificate, error) {
 	/// Decode the PEM block
 	block, _ := pem.Decode(certPEM)
 	if block == nil || block.Type != "CERTIFICATE" {
-		log.Fatalf("Failed to decode PEM block containing the certificate")
+		return nil, fmt.Errorf("failed to decode PEM block containing the certificate")
 	}
 
 	/// Parse the certificate
 	cert, err := x509.ParseCertificate(block.Bytes)
 	if err != nil {
-		log.Fatalf("Error parsing certificate: %v", err)
+		return nil, fmt.Errorf("error parsing certificate: %v", err)
 	}
+	return cert, nil
+}
 
-	/// Compute the fingerprint
-	subject := cert.Subject.ToRDNSequence().String()
-	hash := sha256.Sum256(cert.Raw)
+// CaCertificatePath is where the certificate called name is installed
+func CaCertificatePath(name string) string {
+	return fmt.Sprintf("/usr/local/share/ca-certificates/%s.crt", name)
+}
 
-	return CertificateData{
-		Subject:           subject,
-		Sha256Fingerprint: hash,
+// ReadRemoteCaCertificate reads the installed certificate called name, nil if
+// it doesn't exist
+func ReadRemoteCaCertificate(client *SSHClientContext, name string) (*x509.Certificate, error) {
+	result, err := client.ExecuteCommand(fmt.Sprintf("sudo cat %s 2>/dev/null || true", shellQuote(CaCertificatePath(name))))
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(result) == "" {
+		return nil, nil
 	}
+	return parseCertificatePEM([]byte(result))
 }
 
 func decodeBytesString(hexFingerprint string) ([32]byte, error) {
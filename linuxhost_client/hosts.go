@@ -0,0 +1,166 @@
+package linuxhost_client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+const hostsFile = "/etc/hosts"
+
+// The entries of the provider are kept between these lines, the rest of
+// /etc/hosts is left alone
+const (
+	hostsBegin = "# BEGIN terraform-provider-linuxhost managed entries"
+	hostsEnd   = "# END terraform-provider-linuxhost managed entries"
+)
+
+// hostsLocks serializes the edits of /etc/hosts per host, every entry
+// rewrites it
+var hostsLocks hostLocks
+
+// HostsEntry is a line of /etc/hosts
+type HostsEntry struct {
+	IP        string
+	Hostnames []string
+	Comment   string
+}
+
+func (e *HostsEntry) String() string {
+	line := e.IP + "\t" + strings.Join(e.Hostnames, " ")
+	if e.Comment != "" {
+		line = line + " # " + e.Comment
+	}
+	return line
+}
+
+// sameIP compares addresses regardless of their notation
+func sameIP(a string, b string) bool {
+	addrA, errA := netip.ParseAddr(a)
+	addrB, errB := netip.ParseAddr(b)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+	return addrA == addrB
+}
+
+func parseHostsLine(line string) *HostsEntry {
+	entry, comment, _ := strings.Cut(line, "#")
+	fields := strings.Fields(entry)
+	if len(fields) < 2 {
+		return nil
+	}
+	return &HostsEntry{IP: fields[0], Hostnames: fields[1:], Comment: strings.TrimSpace(comment)}
+}
+
+// splitHostsFile returns the lines before, inside and after the managed
+// block. Without a block all lines are before it.
+func splitHostsFile(content string) ([]string, []string, []string) {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if content == "" {
+		lines = []string{}
+	}
+	begin, end := -1, -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == hostsBegin && begin < 0 {
+			begin = i
+		} else if strings.TrimSpace(line) == hostsEnd && begin >= 0 {
+			end = i
+			break
+		}
+	}
+	if begin < 0 || end < 0 {
+		return lines, nil, nil
+	}
+	return lines[:begin], lines[begin+1 : end], lines[end+1:]
+}
+
+// ParseHostsEntries returns the entries of the managed block
+func ParseHostsEntries(content string) []HostsEntry {
+	entries := []HostsEntry{}
+	_, block, _ := splitHostsFile(content)
+	for _, line := range block {
+		if entry := parseHostsLine(line); entry != nil {
+			entries = append(entries, *entry)
+		}
+	}
+	return entries
+}
+
+// SetHostsEntry returns content with the entry of ip in the managed block
+// replaced by entry, or removed if entry is nil. The block is added at the
+// end if needed and dropped once it's empty.
+func SetHostsEntry(content string, ip string, entry *HostsEntry) string {
+	before, block, after := splitHostsFile(content)
+	lines := []string{}
+	replaced := false
+	for _, line := range block {
+		if existing := parseHostsLine(line); existing != nil && sameIP(existing.IP, ip) {
+			if entry != nil && !replaced {
+				lines = append(lines, entry.String())
+				replaced = true
+			}
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if entry != nil && !replaced {
+		lines = append(lines, entry.String())
+	}
+
+	result := append([]string{}, before...)
+	if len(lines) > 0 {
+		result = append(result, hostsBegin)
+		result = append(result, lines...)
+		result = append(result, hostsEnd)
+	}
+	result = append(result, after...)
+	if len(result) == 0 {
+		return ""
+	}
+	return strings.Join(result, "\n") + "\n"
+}
+
+// ReadHostsEntry returns the managed entry of ip, nil if there is none
+func ReadHostsEntry(connectedClient *SSHClientContext, ip string) (*HostsEntry, error) {
+	content, err := connectedClient.ExecuteCommand("cat " + hostsFile)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range ParseHostsEntries(content) {
+		if sameIP(entry.IP, ip) {
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// WriteHostsEntry adds or replaces the managed entry of ip, nil removes it
+func WriteHostsEntry(connectedClient *SSHClientContext, ip string, entry *HostsEntry) error {
+	lock := hostsLocks.of(connectedClient)
+	lock.Lock()
+	defer lock.Unlock()
+
+	content, err := connectedClient.ExecuteCommand("cat " + hostsFile)
+	if err != nil {
+		return err
+	}
+	updated := SetHostsEntry(content, ip, entry)
+	if updated == content {
+		return nil
+	}
+	// Others may edit the file too, it's only replaced if it's unchanged. The
+	// new file is renamed over it so readers never see half of it, unless
+	// it's a bind mount like in containers which can only be overwritten.
+	sum := sha256.Sum256([]byte(content))
+	cmd := fmt.Sprintf(`[ "$(sha256sum %[1]s | cut -d' ' -f1)" = "%[2]s" ] || { echo '%[1]s changed while it was edited' >&2; exit 1; }
+tmp=$(sudo mktemp %[1]s.linuxhost.XXXXXX) || exit 1
+cat << 'LINUXHOST_EOF' | sudo tee "$tmp" > /dev/null
+%[3]sLINUXHOST_EOF
+sudo chmod 644 "$tmp"
+sudo mv "$tmp" %[1]s 2>/dev/null || { sudo cp "$tmp" %[1]s; status=$?; sudo rm -f "$tmp"; exit $status; }`, hostsFile, hex.EncodeToString(sum[:]), updated)
+	_, err = connectedClient.ExecuteCommand(cmd)
+	return err
+}
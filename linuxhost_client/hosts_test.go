@@ -0,0 +1,54 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+var hostsContent string = `127.0.0.1	localhost
+::1	localhost ip6-localhost
+# The following lines are desirable for IPv6 capable hosts
+` + hostsBegin + `
+10.0.0.10	node1 node1.lab # first node
+2001:db8::a	node2
+` + hostsEnd + `
+192.0.2.1	gateway
+`
+
+func TestParseHostsEntries(t *testing.T) {
+	entries := ParseHostsEntries(hostsContent)
+	if len(entries) != 2 {
+		t.Fatalf("Expected the 2 managed entries, got %+v", entries)
+	}
+	if entries[0].IP != "10.0.0.10" || len(entries[0].Hostnames) != 2 || entries[0].Hostnames[1] != "node1.lab" || entries[0].Comment != "first node" {
+		t.Errorf("Unexpected entry %+v", entries[0])
+	}
+	if len(ParseHostsEntries("127.0.0.1 localhost\n")) != 0 {
+		t.Errorf("Expected no entries without a managed block")
+	}
+}
+
+func TestSetHostsEntry(t *testing.T) {
+	updated := SetHostsEntry(hostsContent, "2001:db8:0::a", &HostsEntry{IP: "2001:db8::a", Hostnames: []string{"node2", "node2.lab"}})
+	expected := `127.0.0.1	localhost
+::1	localhost ip6-localhost
+# The following lines are desirable for IPv6 capable hosts
+` + hostsBegin + `
+10.0.0.10	node1 node1.lab # first node
+2001:db8::a	node2 node2.lab
+` + hostsEnd + `
+192.0.2.1	gateway
+`
+	if updated != expected {
+		t.Errorf("Expected the entry replaced in place, got\n%s", updated)
+	}
+
+	removed := SetHostsEntry(SetHostsEntry(hostsContent, "10.0.0.10", nil), "2001:db8::a", nil)
+	if removed != "127.0.0.1	localhost\n::1	localhost ip6-localhost\n# The following lines are desirable for IPv6 capable hosts\n192.0.2.1	gateway\n" {
+		t.Errorf("Expected the empty block removed, got\n%s", removed)
+	}
+
+	added := SetHostsEntry("127.0.0.1 localhost\n", "10.0.0.11", &HostsEntry{IP: "10.0.0.11", Hostnames: []string{"node3"}})
+	if added != "127.0.0.1 localhost\n"+hostsBegin+"\n10.0.0.11\tnode3\n"+hostsEnd+"\n" {
+		t.Errorf("Expected a block appended, got\n%s", added)
+	}
+}
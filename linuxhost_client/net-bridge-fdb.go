@@ -0,0 +1,139 @@
+package linuxhost_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// BridgeFdb is a forwarding database entry. An all-zero Mac together with Dst
+// is a default destination, as used for VXLAN head-end replication.
+type BridgeFdb struct {
+	Mac    string
+	Dev    string
+	Dst    string
+	Vni    uint32
+	Port   uint32
+	Master bool
+	Self   bool
+	// State is either "permanent" or "static"
+	State string
+}
+
+type bridgeFdbJson struct {
+	Mac    string   `json:"mac"`
+	Ifname string   `json:"ifname"`
+	Dst    string   `json:"dst"`
+	Vni    uint32   `json:"vni"`
+	Port   uint32   `json:"port"`
+	Master string   `json:"master"`
+	Flags  []string `json:"flags"`
+	State  string   `json:"state"`
+}
+
+func (f *BridgeFdb) selector() string {
+	selector := fmt.Sprintf("%s dev %s", f.Mac, f.Dev)
+	if f.Dst != "" {
+		selector = selector + fmt.Sprintf(" dst %s", f.Dst)
+	}
+	if f.Master {
+		selector = selector + " master"
+	}
+	if f.Self {
+		selector = selector + " self"
+	}
+	return selector
+}
+
+// SetBridgeFdb adds the entry. Entries with a destination are appended, so
+// several destinations can share one MAC, all others replace existing ones.
+func SetBridgeFdb(connectedClient *SSHClientContext, fdb *BridgeFdb) error {
+	verb := "replace"
+	if fdb.Dst != "" {
+		verb = "append"
+	}
+	cmd := fmt.Sprintf("sudo bridge fdb %s %s", verb, fdb.selector())
+	if fdb.Vni != 0 {
+		cmd = cmd + fmt.Sprintf(" vni %d", fdb.Vni)
+	}
+	if fdb.Port != 0 {
+		cmd = cmd + fmt.Sprintf(" port %d", fdb.Port)
+	}
+	if fdb.State != "" {
+		cmd = cmd + " " + fdb.State
+	}
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+func DeleteBridgeFdb(connectedClient *SSHClientContext, fdb *BridgeFdb) error {
+	cmd := fmt.Sprintf("sudo bridge fdb del %s", fdb.selector())
+	_, err := connectedClient.ExecuteCommand(cmd)
+	return err
+}
+
+const fdbMissing = "### missing"
+
+// ReadBridgeFdb lists the fdb entries of dev, they're nil if the interface
+// doesn't exist
+func ReadBridgeFdb(connectedClient *SSHClientContext, dev string) ([]BridgeFdb, error) {
+	name := shellQuote(dev)
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("[ -e /sys/class/net/%s ] || { echo '%s'; exit 0; }; bridge -json fdb show dev %s", name, fdbMissing, name))
+	if err != nil {
+		return nil, err
+	}
+	return ParseBridgeFdb(result)
+}
+
+// ParseBridgeFdb parses the output of `bridge -json fdb show`
+func ParseBridgeFdb(output string) ([]BridgeFdb, error) {
+	if strings.TrimSpace(output) == fdbMissing {
+		return nil, nil
+	}
+	entries := []bridgeFdbJson{}
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse bridge fdb output: %w", err)
+	}
+	fdbs := []BridgeFdb{}
+	for _, entry := range entries {
+		fdb := BridgeFdb{
+			Mac:    strings.ToLower(entry.Mac),
+			Dev:    entry.Ifname,
+			Dst:    entry.Dst,
+			Vni:    entry.Vni,
+			Port:   entry.Port,
+			Master: entry.Master != "",
+			State:  entry.State,
+		}
+		for _, flag := range entry.Flags {
+			if flag == "self" {
+				fdb.Self = true
+			}
+		}
+		fdbs = append(fdbs, fdb)
+	}
+	return fdbs, nil
+}
+
+// FindBridgeFdb returns the entry matching the mac, device and destination of
+// target, or nil.
+func FindBridgeFdb(fdbs []BridgeFdb, target *BridgeFdb) *BridgeFdb {
+	for i := range fdbs {
+		fdb := &fdbs[i]
+		if fdb.Dev != target.Dev || !strings.EqualFold(fdb.Mac, target.Mac) || fdb.Dst != target.Dst {
+			continue
+		}
+		if target.Self && !fdb.Self {
+			continue
+		}
+		if target.Master && !fdb.Master {
+			continue
+		}
+		return fdb
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+var bridgeFdbOutput string = `[{"mac":"00:00:00:00:00:00","ifname":"vxlan0","dst":"198.51.100.7","flags":["self"],"state":"permanent"},{"mac":"00:00:00:00:00:00","ifname":"vxlan0","dst":"198.51.100.8","flags":["self"],"state":"permanent"},{"mac":"52:54:00:AA:BB:CC","ifname":"vxlan0","master":"br0","state":"static"},{"mac":"52:54:00:aa:bb:cd","ifname":"vxlan0","dst":"198.51.100.9","vni":200,"port":8472,"flags":["self"],"state":""}]`
+
+func TestParseBridgeFdb(t *testing.T) {
+	fdbs, err := ParseBridgeFdb(bridgeFdbOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(fdbs) != 4 {
+		t.Errorf("There should be 4 fdb entries present, got %d", len(fdbs))
+	}
+
+	missing, err := ParseBridgeFdb(fdbMissing + "\n")
+	if err != nil || missing != nil {
+		t.Errorf("Expected no entries for a missing device, got %v, %v", missing, err)
+	}
+
+	peer := FindBridgeFdb(fdbs, &BridgeFdb{Mac: "00:00:00:00:00:00", Dev: "vxlan0", Dst: "198.51.100.8", Self: true})
+	if peer == nil || peer.State != "permanent" {
+		t.Errorf("Expected the second vxlan0 head-end entry to be permanent, got %+v", peer)
+	}
+
+	host := FindBridgeFdb(fdbs, &BridgeFdb{Mac: "52:54:00:aa:bb:cc", Dev: "vxlan0", Master: true})
+	if host == nil || host.State != "static" || host.Self {
+		t.Errorf("Expected a static master entry, got %+v", host)
+	}
+	if FindBridgeFdb(fdbs, &BridgeFdb{Mac: "52:54:00:aa:bb:cc", Dev: "vxlan0", Self: true}) != nil {
+		t.Errorf("Expected no self entry for 52:54:00:aa:bb:cc")
+	}
+
+	remote := FindBridgeFdb(fdbs, &BridgeFdb{Mac: "52:54:00:aa:bb:cd", Dev: "vxlan0", Dst: "198.51.100.9"})
+	if remote == nil || remote.Vni != 200 || remote.Port != 8472 {
+		t.Errorf("Expected vni and port on the remote entry, got %+v", remote)
+	}
+}
@@ -0,0 +1,140 @@
+package linuxhost_client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// BridgeVlan is a VLAN membership of a bridge port. VidEnd is set for ranges.
+type BridgeVlan struct {
+	Port     string
+	Vid      uint32
+	VidEnd   uint32
+	Pvid     bool
+	Untagged bool
+	Self     bool
+}
+
+func (v *BridgeVlan) vidRange() string {
+	if v.VidEnd != 0 && v.VidEnd != v.Vid {
+		return fmt.Sprintf("%d-%d", v.Vid, v.VidEnd)
+	}
+	return fmt.Sprintf("%d", v.Vid)
+}
+
+func (v *BridgeVlan) lastVid() uint32 {
+	if v.VidEnd > v.Vid {
+		return v.VidEnd
+	}
+	return v.Vid
+}
+
+type bridgeVlanPortJson struct {
+	Ifname string `json:"ifname"`
+	Vlans  []struct {
+		Vid    uint32   `json:"vid"`
+		VidEnd uint32   `json:"vidEnd"`
+		Flags  []string `json:"flags"`
+	} `json:"vlans"`
+}
+
+// SetBridgeVlan adds the VLANs to the port. Adding an existing VLAN replaces its flags.
+func SetBridgeVlan(connectedClient *SSHClientContext, vlan *BridgeVlan) error {
+	cmd := fmt.Sprintf("sudo bridge vlan add dev %s vid %s", vlan.Port, vlan.vidRange())
+	if vlan.Pvid {
+		cmd = cmd + " pvid"
+	}
+	if vlan.Untagged {
+		cmd = cmd + " untagged"
+	}
+	if vlan.Self {
+		cmd = cmd + " self"
+	}
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+func DeleteBridgeVlan(connectedClient *SSHClientContext, vlan *BridgeVlan) error {
+	cmd := fmt.Sprintf("sudo bridge vlan del dev %s vid %s", vlan.Port, vlan.vidRange())
+	if vlan.Self {
+		cmd = cmd + " self"
+	}
+	_, err := connectedClient.ExecuteCommand(cmd)
+	return err
+}
+
+func ReadBridgeVlans(connectedClient *SSHClientContext) ([]BridgeVlan, error) {
+	result, err := connectedClient.ExecuteCommand("bridge -json vlan show")
+	if err != nil {
+		return nil, err
+	}
+	return ParseBridgeVlans(result)
+}
+
+// ParseBridgeVlans flattens `bridge -json vlan show` into one entry per vid
+func ParseBridgeVlans(output string) ([]BridgeVlan, error) {
+	ports := []bridgeVlanPortJson{}
+	if err := json.Unmarshal([]byte(output), &ports); err != nil {
+		return nil, fmt.Errorf("failed to parse bridge vlan output: %w", err)
+	}
+	vlans := []BridgeVlan{}
+	for _, port := range ports {
+		for _, entry := range port.Vlans {
+			pvid := false
+			untagged := false
+			for _, flag := range entry.Flags {
+				if flag == "PVID" {
+					pvid = true
+				}
+				if flag == "Egress Untagged" {
+					untagged = true
+				}
+			}
+			last := entry.VidEnd
+			if last < entry.Vid {
+				last = entry.Vid
+			}
+			for vid := entry.Vid; vid <= last; vid++ {
+				vlans = append(vlans, BridgeVlan{
+					Port:     port.Ifname,
+					Vid:      vid,
+					Pvid:     pvid,
+					Untagged: untagged,
+				})
+			}
+		}
+	}
+	return vlans, nil
+}
+
+// FindBridgeVlan returns the membership matching the port and vid range of
+// target, or nil if any vid of the range is missing.
+func FindBridgeVlan(vlans []BridgeVlan, target *BridgeVlan) *BridgeVlan {
+	byVid := map[uint32]BridgeVlan{}
+	for _, v := range vlans {
+		if v.Port != target.Port {
+			continue
+		}
+		byVid[v.Vid] = v
+	}
+	found := &BridgeVlan{
+		Port:     target.Port,
+		Vid:      target.Vid,
+		VidEnd:   target.VidEnd,
+		Self:     target.Self,
+		Untagged: true,
+	}
+	for vid := target.Vid; vid <= target.lastVid(); vid++ {
+		v, ok := byVid[vid]
+		if !ok {
+			return nil
+		}
+		found.Pvid = found.Pvid || v.Pvid
+		found.Untagged = found.Untagged && v.Untagged
+	}
+	return found
+}
@@ -0,0 +1,35 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+var bridgeVlanOutput string = `[{"ifname":"br0","vlans":[{"vid":1,"flags":["PVID","Egress Untagged"]}]},{"ifname":"eth1","vlans":[{"vid":10,"flags":["PVID","Egress Untagged"]},{"vid":20},{"vid":21},{"vid":22}]},{"ifname":"vxlan0","vlans":[{"vid":100,"vidEnd":102}]}]`
+
+func TestParseBridgeVlans(t *testing.T) {
+	vlans, err := ParseBridgeVlans(bridgeVlanOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(vlans) != 8 {
+		t.Errorf("There should be 8 vlan entries present, got %d", len(vlans))
+	}
+
+	access := FindBridgeVlan(vlans, &BridgeVlan{Port: "eth1", Vid: 10})
+	if access == nil || !access.Pvid || !access.Untagged {
+		t.Errorf("Expected eth1 vid 10 to be pvid untagged, got %+v", access)
+	}
+
+	trunk := FindBridgeVlan(vlans, &BridgeVlan{Port: "eth1", Vid: 20, VidEnd: 22})
+	if trunk == nil || trunk.Pvid || trunk.Untagged {
+		t.Errorf("Expected eth1 vid 20-22 to be tagged, got %+v", trunk)
+	}
+
+	if FindBridgeVlan(vlans, &BridgeVlan{Port: "eth1", Vid: 20, VidEnd: 23}) != nil {
+		t.Errorf("Expected eth1 vid 20-23 to be missing")
+	}
+
+	if FindBridgeVlan(vlans, &BridgeVlan{Port: "vxlan0", Vid: 101}) == nil {
+		t.Errorf("Expected vxlan0 vid 101 to be expanded from its range")
+	}
+}
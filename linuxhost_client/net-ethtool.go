@@ -0,0 +1,334 @@
+package linuxhost_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EthtoolSettings are the settings of a NIC changed with ethtool. Nil maps
+// and zero values leave the current value.
+type EthtoolSettings struct {
+	// Features are the offloads of `ethtool -k`, by long name or short alias
+	Features map[string]bool
+	// Ring sizes by ethtool parameter: rx, rx-mini, rx-jumbo and tx
+	Ring map[string]uint32
+	// Channel counts: rx, tx, other and combined
+	Channels map[string]uint32
+	// Coalesce parameters like rx-usecs or tx-frames
+	Coalesce   map[string]uint32
+	AdaptiveRx *bool
+	AdaptiveTx *bool
+
+	// Speed in Mb/s
+	Speed   uint32
+	Duplex  string
+	Autoneg *bool
+
+	// FixedFeatures can't be changed on the device, only set when read
+	FixedFeatures map[string]bool
+}
+
+// ethtoolFeatureAliases maps the short names ethtool -K accepts to the names
+// ethtool -k reports
+var ethtoolFeatureAliases = map[string]string{
+	"rx":     "rx-checksumming",
+	"tx":     "tx-checksumming",
+	"sg":     "scatter-gather",
+	"tso":    "tcp-segmentation-offload",
+	"ufo":    "udp-fragmentation-offload",
+	"gso":    "generic-segmentation-offload",
+	"gro":    "generic-receive-offload",
+	"lro":    "large-receive-offload",
+	"rxvlan": "rx-vlan-offload",
+	"txvlan": "tx-vlan-offload",
+	"ntuple": "ntuple-filters",
+	"rxhash": "receive-hashing",
+}
+
+// EthtoolFeatureName returns the name ethtool -k reports for a feature
+func EthtoolFeatureName(feature string) string {
+	if long, ok := ethtoolFeatureAliases[feature]; ok {
+		return long
+	}
+	return feature
+}
+
+func sortedKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// EthtoolCommands returns the ethtool arguments changing current to desired,
+// ethtool fails on parameters that don't change so only differences are set
+func EthtoolCommands(dev string, desired *EthtoolSettings, current *EthtoolSettings) []string {
+	commands := []string{}
+
+	features := ""
+	for _, feature := range sortedKeys(desired.Features) {
+		if value, ok := current.Features[EthtoolFeatureName(feature)]; !ok || value != desired.Features[feature] {
+			features = features + fmt.Sprintf(" %s %s", feature, onOff(desired.Features[feature]))
+		}
+	}
+	if features != "" {
+		commands = append(commands, "-K "+dev+features)
+	}
+
+	numbers := func(option string, desired map[string]uint32, current map[string]uint32, extra string) {
+		args := extra
+		for _, key := range sortedKeys(desired) {
+			if value, ok := current[key]; !ok || value != desired[key] {
+				args = args + fmt.Sprintf(" %s %d", key, desired[key])
+			}
+		}
+		if args != "" {
+			commands = append(commands, option+" "+dev+args)
+		}
+	}
+	numbers("-G", desired.Ring, current.Ring, "")
+	numbers("-L", desired.Channels, current.Channels, "")
+
+	adaptive := ""
+	if desired.AdaptiveRx != nil && (current.AdaptiveRx == nil || *current.AdaptiveRx != *desired.AdaptiveRx) {
+		adaptive = adaptive + " adaptive-rx " + onOff(*desired.AdaptiveRx)
+	}
+	if desired.AdaptiveTx != nil && (current.AdaptiveTx == nil || *current.AdaptiveTx != *desired.AdaptiveTx) {
+		adaptive = adaptive + " adaptive-tx " + onOff(*desired.AdaptiveTx)
+	}
+	numbers("-C", desired.Coalesce, current.Coalesce, adaptive)
+
+	link := ""
+	if desired.Autoneg != nil && (current.Autoneg == nil || *current.Autoneg != *desired.Autoneg) {
+		link = link + " autoneg " + onOff(*desired.Autoneg)
+	}
+	// Speed and duplex are set together, the kernel rejects half a mode
+	if (desired.Speed != 0 && desired.Speed != current.Speed) || (desired.Duplex != "" && desired.Duplex != current.Duplex) {
+		if speed := desired.Speed; speed != 0 || current.Speed != 0 {
+			if speed == 0 {
+				speed = current.Speed
+			}
+			link = link + fmt.Sprintf(" speed %d", speed)
+		}
+		if duplex := desired.Duplex; duplex != "" || current.Duplex != "" {
+			if duplex == "" {
+				duplex = current.Duplex
+			}
+			link = link + " duplex " + duplex
+		}
+	}
+	if link != "" {
+		commands = append(commands, "-s "+dev+link)
+	}
+	return commands
+}
+
+// SetEthtool applies the settings that differ from the current ones
+func SetEthtool(connectedClient *SSHClientContext, dev string, desired *EthtoolSettings) error {
+	current, err := ReadEthtool(connectedClient, dev)
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("interface %s does not exist", dev)
+	}
+	for _, args := range EthtoolCommands(dev, desired, current) {
+		if _, err := connectedClient.ExecuteCommand("sudo ethtool " + args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EthtoolDrift lists the settings of desired that current doesn't have
+func EthtoolDrift(desired *EthtoolSettings, current *EthtoolSettings) []string {
+	drift := []string{}
+	for _, feature := range sortedKeys(desired.Features) {
+		if value, ok := current.Features[EthtoolFeatureName(feature)]; !ok || value != desired.Features[feature] {
+			reason := ""
+			if current.FixedFeatures[EthtoolFeatureName(feature)] {
+				reason = " (fixed)"
+			}
+			drift = append(drift, "feature "+feature+reason)
+		}
+	}
+	for _, section := range []struct {
+		name             string
+		desired, current map[string]uint32
+	}{
+		{"ring", desired.Ring, current.Ring},
+		{"channels", desired.Channels, current.Channels},
+		{"coalesce", desired.Coalesce, current.Coalesce},
+	} {
+		for _, key := range sortedKeys(section.desired) {
+			if value, ok := section.current[key]; !ok || value != section.desired[key] {
+				drift = append(drift, section.name+" "+key)
+			}
+		}
+	}
+	boolDrift := func(name string, desired *bool, current *bool) {
+		if desired != nil && (current == nil || *current != *desired) {
+			drift = append(drift, name)
+		}
+	}
+	boolDrift("coalesce adaptive-rx", desired.AdaptiveRx, current.AdaptiveRx)
+	boolDrift("coalesce adaptive-tx", desired.AdaptiveTx, current.AdaptiveTx)
+	boolDrift("autoneg", desired.Autoneg, current.Autoneg)
+	if desired.Speed != 0 && desired.Speed != current.Speed {
+		drift = append(drift, "speed")
+	}
+	if desired.Duplex != "" && desired.Duplex != current.Duplex {
+		drift = append(drift, "duplex")
+	}
+	return drift
+}
+
+const ethtoolMissing = "### missing"
+
+// ReadEthtool reads the current settings, nil if the interface doesn't
+// exist. Sections use `ethtool --json` where the installed ethtool supports
+// it and fall back to the text output otherwise.
+func ReadEthtool(connectedClient *SSHClientContext, dev string) (*EthtoolSettings, error) {
+	name := shellQuote(dev)
+	section := func(option string) string {
+		return fmt.Sprintf("echo '### %[1]s'; sudo ethtool --json %[1]s %[2]s 2>/dev/null || sudo ethtool %[1]s %[2]s 2>&1 || true; ", option, name)
+	}
+	cmd := fmt.Sprintf("[ -e /sys/class/net/%s ] || { echo '%s'; exit 0; }; ", name, ethtoolMissing) +
+		section("-k") + section("-g") + section("-l") + section("-c") +
+		fmt.Sprintf("echo '### -s'; sudo ethtool %s 2>&1 || true", name)
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return ParseEthtool(result), nil
+}
+
+// ParseEthtool parses the sections of ReadEthtool
+func ParseEthtool(output string) *EthtoolSettings {
+	if strings.TrimSpace(output) == ethtoolMissing {
+		return nil
+	}
+	sections := map[string]string{}
+	current := ""
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "### ") {
+			current = strings.TrimPrefix(line, "### ")
+			continue
+		}
+		sections[current] = sections[current] + line + "\n"
+	}
+
+	settings := &EthtoolSettings{
+		Features:      map[string]bool{},
+		FixedFeatures: map[string]bool{},
+		Ring:          map[string]uint32{},
+		Channels:      map[string]uint32{},
+		Coalesce:      map[string]uint32{},
+	}
+
+	for key, value := range ParseEthtoolValues(sections["-k"]) {
+		fields := strings.Fields(value)
+		if len(fields) == 0 || (fields[0] != "on" && fields[0] != "off" && fields[0] != "true" && fields[0] != "false") {
+			continue
+		}
+		settings.Features[key] = fields[0] == "on" || fields[0] == "true"
+		if strings.Contains(value, "[fixed]") {
+			settings.FixedFeatures[key] = true
+		}
+	}
+	numbers := func(section string, target map[string]uint32) map[string]string {
+		values := ParseEthtoolValues(section)
+		for key, value := range values {
+			if strings.HasSuffix(key, "-max") {
+				continue
+			}
+			if v, err := strconv.ParseUint(value, 10, 32); err == nil {
+				target[key] = uint32(v)
+			}
+		}
+		return values
+	}
+	numbers(sections["-g"], settings.Ring)
+	numbers(sections["-l"], settings.Channels)
+	coalesce := numbers(sections["-c"], settings.Coalesce)
+	if value, ok := coalesce["adaptive-rx"]; ok {
+		on := value == "on" || value == "true"
+		settings.AdaptiveRx = &on
+	}
+	if value, ok := coalesce["adaptive-tx"]; ok {
+		on := value == "on" || value == "true"
+		settings.AdaptiveTx = &on
+	}
+
+	link := ParseEthtoolValues(sections["-s"])
+	if speed, err := strconv.ParseUint(strings.TrimSuffix(link["speed"], "Mb/s"), 10, 32); err == nil {
+		settings.Speed = uint32(speed)
+	}
+	if duplex := strings.ToLower(link["duplex"]); duplex == "half" || duplex == "full" {
+		settings.Duplex = duplex
+	}
+	if autoneg, ok := link["auto-negotiation"]; ok {
+		on := autoneg == "on"
+		settings.Autoneg = &on
+	}
+	return settings
+}
+
+// ParseEthtoolValues returns the values of one ethtool section, from the
+// first object of its JSON output or the "Key: value" lines of its text
+// output. Text keys are lower case with dashes, like the JSON keys. Of the
+// text of -g and -l only the current settings are kept, not the maximums.
+// JSON features like {"active": true, "fixed": true} become "on [fixed]"
+// as in the text output.
+func ParseEthtoolValues(section string) map[string]string {
+	values := map[string]string{}
+	if trimmed := strings.TrimSpace(section); strings.HasPrefix(trimmed, "[") {
+		var objects []map[string]any
+		if err := json.Unmarshal([]byte(trimmed), &objects); err == nil && len(objects) > 0 {
+			for key, value := range objects[0] {
+				switch v := value.(type) {
+				case string:
+					values[key] = v
+				case bool:
+					values[key] = strconv.FormatBool(v)
+				case float64:
+					values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+				case map[string]any:
+					if active, ok := v["active"].(bool); ok {
+						values[key] = onOff(active)
+						if fixed, _ := v["fixed"].(bool); fixed {
+							values[key] = values[key] + " [fixed]"
+						}
+					}
+				}
+			}
+			return values
+		}
+	}
+	for _, line := range strings.Split(section, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "Current hardware settings:") {
+			values = map[string]string{}
+			continue
+		}
+		// "Adaptive RX: on  TX: off"
+		if rest, found := strings.CutPrefix(trimmed, "Adaptive RX:"); found {
+			rx, tx, _ := strings.Cut(rest, "TX:")
+			values["adaptive-rx"] = strings.TrimSpace(rx)
+			values["adaptive-tx"] = strings.TrimSpace(tx)
+			continue
+		}
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found || key == "" {
+			continue
+		}
+		key = strings.ReplaceAll(strings.ToLower(key), " ", "-")
+		values[key] = strings.TrimSpace(value)
+	}
+	return values
+}
@@ -0,0 +1,189 @@
+package linuxhost_client
+
+import (
+	"reflect"
+	"testing"
+)
+
+var ethtoolTextOutput string = `### -k
+Features for eth0:
+rx-checksumming: on
+tx-checksumming: on
+	tx-checksum-ipv4: off [fixed]
+scatter-gather: on
+tcp-segmentation-offload: on
+generic-receive-offload: on
+large-receive-offload: off [fixed]
+### -g
+Ring parameters for eth0:
+Pre-set maximums:
+RX:		8192
+RX Mini:	n/a
+RX Jumbo:	0
+TX:		8192
+Current hardware settings:
+RX:		1024
+RX Mini:	n/a
+RX Jumbo:	0
+TX:		1024
+### -l
+Channel parameters for eth0:
+Pre-set maximums:
+RX:		n/a
+TX:		n/a
+Other:		1
+Combined:	63
+Current hardware settings:
+RX:		n/a
+TX:		n/a
+Other:		1
+Combined:	16
+### -c
+Coalesce parameters for eth0:
+Adaptive RX: on  TX: off
+stats-block-usecs: n/a
+rx-usecs: 50
+rx-frames: n/a
+tx-usecs: 50
+tx-frames: n/a
+### -s
+Settings for eth0:
+	Speed: 25000Mb/s
+	Duplex: Full
+	Auto-negotiation: off
+	Link detected: yes
+`
+
+var ethtoolJsonOutput string = `### -k
+[ {
+        "ifname": "eth0",
+        "rx-checksumming": {
+            "active": true,
+            "fixed": false,
+            "requested": true
+        },
+        "tx-checksum-ipv4": {
+            "active": false,
+            "fixed": true,
+            "requested": false
+        },
+        "generic-receive-offload": {
+            "active": false,
+            "fixed": false,
+            "requested": true
+        },
+        "large-receive-offload": {
+            "active": true,
+            "fixed": true,
+            "requested": true
+        }
+    } ]
+### -g
+[ {
+        "ifname": "eth0",
+        "rx-max": 4096,
+        "tx-max": 4096,
+        "rx": 512,
+        "tx": 256
+    } ]
+### -l
+[ {
+        "ifname": "eth0",
+        "combined-max": 8,
+        "combined": 4
+    } ]
+### -c
+[ {
+        "ifname": "eth0",
+        "adaptive-rx": false,
+        "adaptive-tx": true,
+        "rx-usecs": 8
+    } ]
+### -s
+Settings for eth0:
+	Speed: Unknown!
+	Duplex: Unknown! (255)
+	Auto-negotiation: on
+`
+
+func TestParseEthtoolText(t *testing.T) {
+	settings := ParseEthtool(ethtoolTextOutput)
+	if settings == nil {
+		t.Fatalf("Expected settings")
+	}
+	if !settings.Features["generic-receive-offload"] || settings.Features["tx-checksum-ipv4"] || !settings.FixedFeatures["large-receive-offload"] || settings.FixedFeatures["scatter-gather"] {
+		t.Errorf("Unexpected features %v fixed %v", settings.Features, settings.FixedFeatures)
+	}
+	if !reflect.DeepEqual(settings.Ring, map[string]uint32{"rx": 1024, "rx-jumbo": 0, "tx": 1024}) {
+		t.Errorf("Expected the current ring sizes, got %v", settings.Ring)
+	}
+	if !reflect.DeepEqual(settings.Channels, map[string]uint32{"other": 1, "combined": 16}) {
+		t.Errorf("Expected the current channels, got %v", settings.Channels)
+	}
+	if settings.Coalesce["rx-usecs"] != 50 || settings.AdaptiveRx == nil || !*settings.AdaptiveRx || settings.AdaptiveTx == nil || *settings.AdaptiveTx {
+		t.Errorf("Unexpected coalescing %v rx %v tx %v", settings.Coalesce, settings.AdaptiveRx, settings.AdaptiveTx)
+	}
+	if settings.Speed != 25000 || settings.Duplex != "full" || settings.Autoneg == nil || *settings.Autoneg {
+		t.Errorf("Unexpected link %d %s %v", settings.Speed, settings.Duplex, settings.Autoneg)
+	}
+}
+
+func TestParseEthtoolJson(t *testing.T) {
+	settings := ParseEthtool(ethtoolJsonOutput)
+	expectedFeatures := map[string]bool{"rx-checksumming": true, "tx-checksum-ipv4": false, "generic-receive-offload": false, "large-receive-offload": true}
+	if !reflect.DeepEqual(settings.Features, expectedFeatures) {
+		t.Errorf("Expected the active features %v, got %v", expectedFeatures, settings.Features)
+	}
+	if !reflect.DeepEqual(settings.FixedFeatures, map[string]bool{"tx-checksum-ipv4": true, "large-receive-offload": true}) {
+		t.Errorf("Unexpected fixed features %v", settings.FixedFeatures)
+	}
+	if !reflect.DeepEqual(settings.Ring, map[string]uint32{"rx": 512, "tx": 256}) {
+		t.Errorf("Expected the ring sizes without maximums, got %v", settings.Ring)
+	}
+	if !reflect.DeepEqual(settings.Channels, map[string]uint32{"combined": 4}) {
+		t.Errorf("Expected the channels without maximums, got %v", settings.Channels)
+	}
+	if settings.Coalesce["rx-usecs"] != 8 || *settings.AdaptiveRx || !*settings.AdaptiveTx {
+		t.Errorf("Unexpected coalescing %v", settings.Coalesce)
+	}
+	if settings.Speed != 0 || settings.Duplex != "" || !*settings.Autoneg {
+		t.Errorf("Expected an unknown link mode, got %d %s", settings.Speed, settings.Duplex)
+	}
+	if ParseEthtool(ethtoolMissing+"\n") != nil {
+		t.Errorf("Expected nil for a missing interface")
+	}
+}
+
+func TestEthtoolCommands(t *testing.T) {
+	current := ParseEthtool(ethtoolTextOutput)
+	on, off := true, false
+	desired := &EthtoolSettings{
+		Features:   map[string]bool{"gro": false, "tso": true, "lro": true},
+		Ring:       map[string]uint32{"rx": 4096, "tx": 1024},
+		Channels:   map[string]uint32{"combined": 16},
+		Coalesce:   map[string]uint32{"rx-usecs": 100},
+		AdaptiveRx: &off,
+		Autoneg:    &off,
+		Duplex:     "half",
+	}
+	commands := EthtoolCommands("eth0", desired, current)
+	expected := []string{
+		"-K eth0 gro off lro on",
+		"-G eth0 rx 4096",
+		"-C eth0 adaptive-rx off rx-usecs 100",
+		"-s eth0 speed 25000 duplex half",
+	}
+	if !reflect.DeepEqual(commands, expected) {
+		t.Errorf("Expected %v, got %v", expected, commands)
+	}
+
+	drift := EthtoolDrift(desired, current)
+	expectedDrift := []string{"feature gro", "feature lro (fixed)", "ring rx", "coalesce rx-usecs", "coalesce adaptive-rx", "duplex"}
+	if !reflect.DeepEqual(drift, expectedDrift) {
+		t.Errorf("Expected drift %v, got %v", expectedDrift, drift)
+	}
+
+	if len(EthtoolCommands("eth0", &EthtoolSettings{AdaptiveRx: &on, Ring: map[string]uint32{"rx": 1024}}, current)) != 0 {
+		t.Errorf("Expected no commands without changes")
+	}
+}
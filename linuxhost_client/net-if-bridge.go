@@ -4,6 +4,22 @@ import "fmt"
 
 type IfBridge struct {
 	IfCommon
+	VlanFiltering   bool
+	VlanDefaultPvid *uint32
+	VlanProtocol    string
+
+	// Optional tuning, nil leaves the kernel default or current value
+	Stp              *bool
+	ForwardDelay     *uint32
+	HelloTime        *uint32
+	MaxAge           *uint32
+	AgeingTime       *uint32
+	Priority         *uint32
+	GroupFwdMask     *uint32
+	McastSnooping    *bool
+	McastQuerier     *bool
+	McastIgmpVersion *uint32
+	McastMldVersion  *uint32
 }
 
 var _ IsIf = &IfBridge{}
@@ -12,8 +28,53 @@ func (m *IfBridge) GetCommon() *IfCommon {
 	return &m.IfCommon
 }
 
+func (m *IfBridge) bridgeOptions() string {
+	vlanFiltering := 0
+	if m.VlanFiltering {
+		vlanFiltering = 1
+	}
+	options := fmt.Sprintf(" vlan_filtering %d", vlanFiltering)
+	options = options + uint32Option("vlan_default_pvid", m.VlanDefaultPvid)
+	if m.VlanProtocol != "" {
+		options = options + fmt.Sprintf(" vlan_protocol %s", m.VlanProtocol)
+	}
+	options = options + boolOption("stp_state", m.Stp)
+	options = options + uint32Option("forward_delay", m.ForwardDelay)
+	options = options + uint32Option("hello_time", m.HelloTime)
+	options = options + uint32Option("max_age", m.MaxAge)
+	options = options + uint32Option("ageing_time", m.AgeingTime)
+	options = options + uint32Option("priority", m.Priority)
+	options = options + uint32Option("group_fwd_mask", m.GroupFwdMask)
+	options = options + boolOption("mcast_snooping", m.McastSnooping)
+	options = options + boolOption("mcast_querier", m.McastQuerier)
+	options = options + uint32Option("mcast_igmp_version", m.McastIgmpVersion)
+	options = options + uint32Option("mcast_mld_version", m.McastMldVersion)
+	return options
+}
+
+func (m *IfBridge) OptionsEqual(other *IfBridge) bool {
+	return m.bridgeOptions() == other.bridgeOptions()
+}
+
+func uint32Option(name string, value *uint32) string {
+	if value == nil {
+		return ""
+	}
+	return fmt.Sprintf(" %s %d", name, *value)
+}
+
+func boolOption(name string, value *bool) string {
+	if value == nil {
+		return ""
+	}
+	if *value {
+		return fmt.Sprintf(" %s 1", name)
+	}
+	return fmt.Sprintf(" %s 0", name)
+}
+
 func CreateIfBridge(connectedClient *SSHClientContext, iface *IfBridge) (*IfBridge, error) {
-	cmd := fmt.Sprintf("sudo ip link add %s type bridge", iface.Name)
+	cmd := fmt.Sprintf("sudo ip link add %s%s type bridge%s", iface.Name, iface.netnsOption(), iface.bridgeOptions())
 	result, err := connectedClient.ExecuteCommand(cmd)
 	if err != nil {
 		return nil, err
@@ -24,3 +85,14 @@ func CreateIfBridge(connectedClient *SSHClientContext, iface *IfBridge) (*IfBrid
 	}
 	return iface, nil
 }
+
+// IfSetBridgeOptions updates the bridge options in place
+func IfSetBridgeOptions(connectedClient *SSHClientContext, iface *IfBridge) error {
+	cmd := fmt.Sprintf("%s link set %s type bridge%s", NetnsCommand(iface.Netns, "ip"), iface.Name, iface.bridgeOptions())
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
@@ -2,12 +2,16 @@ package linuxhost_client
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 func IfSetCommon(connectedClient *SSHClientContext, ifaceX IsIf) *error {
 	steps := []func(*SSHClientContext, IsIf) *error{
+		IfSetLink,
 		IfSetState,
 		IfSetBridgeMaster,
+		IfSetBridgePort,
 	}
 	for _, step := range steps {
 		if err := step(connectedClient, ifaceX); err != nil {
@@ -17,12 +21,113 @@ func IfSetCommon(connectedClient *SSHClientContext, ifaceX IsIf) *error {
 	return nil
 }
 
+// NetnsCommand returns the sudo prefix running tool inside netns. Named
+// namespaces use `ip -n` or `ip netns exec`, PIDs use nsenter.
+func NetnsCommand(netns string, tool string) string {
+	if netns == "" {
+		return "sudo " + tool
+	}
+	if _, err := strconv.ParseUint(netns, 10, 32); err == nil {
+		return fmt.Sprintf("sudo nsenter -t %s -n %s", netns, tool)
+	}
+	if tool == "ip" || tool == "bridge" {
+		return fmt.Sprintf("sudo %s -n %s", tool, netns)
+	}
+	return fmt.Sprintf("sudo ip netns exec %s %s", netns, tool)
+}
+
+// netnsOption creates the interface directly in its netns with `ip link add`,
+// moving it there afterwards fails when the root namespace has the name taken
+func (iface *IfCommon) netnsOption() string {
+	if iface.Netns == "" {
+		return ""
+	}
+	return fmt.Sprintf(" netns %s", iface.Netns)
+}
+
+func (iface *IfCommon) linkOptions() string {
+	options := ""
+	if iface.Mac != "" {
+		options = options + fmt.Sprintf(" address %s", iface.Mac)
+	}
+	if iface.Mtu != 0 {
+		options = options + fmt.Sprintf(" mtu %d", iface.Mtu)
+	}
+	if iface.TxQueueLen != 0 {
+		options = options + fmt.Sprintf(" txqueuelen %d", iface.TxQueueLen)
+	}
+	if iface.Group != "" {
+		options = options + fmt.Sprintf(" group %s", iface.Group)
+	}
+	if iface.Alias != "" {
+		options = options + " alias " + shellQuote(iface.Alias)
+	}
+	return options
+}
+
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// IfSetLink applies the mac, mtu, txqueuelen, group and alias
+func IfSetLink(connectedClient *SSHClientContext, ifaceX IsIf) *error {
+	iface := ifaceX.GetCommon()
+	options := iface.linkOptions()
+	if options == "" {
+		return nil
+	}
+	cmd := fmt.Sprintf("%s link set %s%s", NetnsCommand(iface.Netns, "ip"), iface.Name, options)
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return &err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+// IfUpdateLink applies the link settings that differ between desired and
+// state. An alias removed from desired is cleared.
+func IfUpdateLink(connectedClient *SSHClientContext, desiredX IsIf, stateX IsIf) *error {
+	desired := desiredX.GetCommon()
+	state := stateX.GetCommon()
+	changed := IfCommon{Name: desired.Name, Netns: desired.Netns}
+	if desired.Mac != state.Mac {
+		changed.Mac = desired.Mac
+	}
+	if desired.Mtu != state.Mtu {
+		changed.Mtu = desired.Mtu
+	}
+	if desired.TxQueueLen != state.TxQueueLen {
+		changed.TxQueueLen = desired.TxQueueLen
+	}
+	if desired.Group != state.Group {
+		changed.Group = desired.Group
+	}
+	if desired.Alias != state.Alias {
+		changed.Alias = desired.Alias
+	}
+	options := changed.linkOptions()
+	if desired.Alias == "" && state.Alias != "" {
+		options = options + " alias ''"
+	}
+	if options == "" {
+		return nil
+	}
+	cmd := fmt.Sprintf("%s link set %s%s", NetnsCommand(desired.Netns, "ip"), desired.Name, options)
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return &err
+	}
+	fmt.Println(result)
+	return nil
+}
+
 func IfSetState(connectedClient *SSHClientContext, ifaceX IsIf) *error {
 	iface := ifaceX.GetCommon()
 	if iface.State == "" {
 		return nil
 	}
-	cmd := fmt.Sprintf("sudo ip link set %s %s", iface.Name, iface.State)
+	cmd := fmt.Sprintf("%s link set %s %s", NetnsCommand(iface.Netns, "ip"), iface.Name, iface.State)
 	result, err := connectedClient.ExecuteCommand(cmd)
 	if err != nil {
 		return &err
@@ -37,10 +142,54 @@ func IfSetBridgeMaster(connectedClient *SSHClientContext, ifaceX IsIf) *error {
 	var cmd string
 	
 	if iface.BridgeMember == nil {
-		cmd = fmt.Sprintf("sudo ip link set %s nomaster", iface.Name)
+		cmd = fmt.Sprintf("%s link set %s nomaster", NetnsCommand(iface.Netns, "ip"), iface.Name)
 	} else {
-		cmd = fmt.Sprintf("sudo ip link set %s master %s", iface.Name, iface.BridgeMember.Name)
+		cmd = fmt.Sprintf("%s link set %s master %s", NetnsCommand(iface.Netns, "ip"), iface.Name, iface.BridgeMember.Name)
+	}
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return &err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+func (m *IfBridgeMember) portOptions() string {
+	options := ""
+	options = options + uint32Option("cost", m.Cost)
+	options = options + uint32Option("priority", m.Priority)
+	options = options + onOffOption("hairpin", m.Hairpin)
+	options = options + onOffOption("learning", m.Learning)
+	options = options + onOffOption("flood", m.Flood)
+	options = options + onOffOption("mcast_flood", m.McastFlood)
+	options = options + onOffOption("neigh_suppress", m.NeighSuppress)
+	options = options + onOffOption("isolated", m.Isolated)
+	options = options + onOffOption("guard", m.BpduGuard)
+	return options
+}
+
+func onOffOption(name string, value *bool) string {
+	if value == nil {
+		return ""
+	}
+	if *value {
+		return fmt.Sprintf(" %s on", name)
+	}
+	return fmt.Sprintf(" %s off", name)
+}
+
+// IfSetBridgePort applies the port settings of a bridge member. It does
+// nothing if the interface isn't a member or no settings are given.
+func IfSetBridgePort(connectedClient *SSHClientContext, ifaceX IsIf) *error {
+	iface := ifaceX.GetCommon()
+	if iface.BridgeMember == nil {
+		return nil
+	}
+	options := iface.BridgeMember.portOptions()
+	if options == "" {
+		return nil
 	}
+	cmd := fmt.Sprintf("%s link set dev %s%s", NetnsCommand(iface.Netns, "bridge"), iface.Name, options)
 	result, err := connectedClient.ExecuteCommand(cmd)
 	if err != nil {
 		return &err
@@ -0,0 +1,25 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+func TestNetnsCommand(t *testing.T) {
+	cases := []struct {
+		netns    string
+		tool     string
+		expected string
+	}{
+		{"", "ip", "sudo ip"},
+		{"tenant1", "ip", "sudo ip -n tenant1"},
+		{"tenant1", "bridge", "sudo bridge -n tenant1"},
+		{"tenant1", "wg", "sudo ip netns exec tenant1 wg"},
+		{"4242", "ip", "sudo nsenter -t 4242 -n ip"},
+		{"4242", "wg", "sudo nsenter -t 4242 -n wg"},
+	}
+	for _, c := range cases {
+		if got := NetnsCommand(c.netns, c.tool); got != c.expected {
+			t.Errorf("NetnsCommand(%q, %q) = %q, expected %q", c.netns, c.tool, got, c.expected)
+		}
+	}
+}
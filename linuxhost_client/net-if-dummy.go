@@ -0,0 +1,26 @@
+package linuxhost_client
+
+import "fmt"
+
+type IfDummy struct {
+	IfCommon
+}
+
+var _ IsIf = &IfDummy{}
+
+func (m *IfDummy) GetCommon() *IfCommon {
+	return &m.IfCommon
+}
+
+func CreateIfDummy(connectedClient *SSHClientContext, iface *IfDummy) (*IfDummy, error) {
+	cmd := fmt.Sprintf("sudo ip link add %s%s type dummy", iface.Name, iface.netnsOption())
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println(result)
+	if err := IfSetCommon(connectedClient, iface); err != nil {
+		return nil, *err
+	}
+	return iface, nil
+}
@@ -0,0 +1,89 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// IfTunnel covers the point-to-point ip tunnels: gre, gretap, ipip and sit
+type IfTunnel struct {
+	IfCommon
+	Kind     string
+	Local    string
+	Remote   string
+	Ttl      uint32
+	Key      *uint32
+	PmtuDisc bool
+}
+
+var _ IsIf = &IfTunnel{}
+
+func (m *IfTunnel) GetCommon() *IfCommon {
+	return &m.IfCommon
+}
+
+type TunnelInfo struct {
+	Kind   string
+	Local  string
+	Remote string
+	// Ttl of 0 means the ttl is inherited
+	Ttl      uint32
+	Key      *uint32
+	PmtuDisc bool
+}
+
+func CreateIfTunnel(connectedClient *SSHClientContext, iface *IfTunnel) (*IfTunnel, error) {
+	cmd := fmt.Sprintf("sudo ip link add %s%s type %s remote %s", iface.Name, iface.netnsOption(), iface.Kind, iface.Remote)
+	if iface.Local != "" {
+		cmd = cmd + fmt.Sprintf(" local %s", iface.Local)
+	}
+	if iface.Ttl == 0 {
+		cmd = cmd + " ttl inherit"
+	} else {
+		cmd = cmd + fmt.Sprintf(" ttl %d", iface.Ttl)
+	}
+	if iface.Key != nil {
+		cmd = cmd + fmt.Sprintf(" key %d", *iface.Key)
+	}
+	if iface.PmtuDisc {
+		cmd = cmd + " pmtudisc"
+	} else {
+		cmd = cmd + " nopmtudisc"
+	}
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println(result)
+	if err := IfSetCommon(connectedClient, iface); err != nil {
+		return nil, *err
+	}
+	return iface, nil
+}
+
+// parseTunnelKey accepts both the dotted quad form `ip` prints and a plain number
+func parseTunnelKey(value string) *uint32 {
+	if strings.Contains(value, ".") {
+		ip := net.ParseIP(value).To4()
+		if ip == nil {
+			return nil
+		}
+		key := uint32(ip[0])<<24 | uint32(ip[1])<<16 | uint32(ip[2])<<8 | uint32(ip[3])
+		return &key
+	}
+	v, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return nil
+	}
+	key := uint32(v)
+	return &key
+}
+
+func tunnelAddress(value string) string {
+	if value == "any" {
+		return ""
+	}
+	return value
+}
@@ -4,6 +4,10 @@ import "fmt"
 
 type IfVethPeer struct {
 	IfCommon
+	// PeerName is the other end of the pair
+	PeerName string
+	// Local is set on the end the pair is created from
+	Local bool
 }
 
 var _ IsIf = &IfVethPeer{}
@@ -18,7 +22,7 @@ type IfVethPair struct {
 }
 
 func CreateIfVeth(connectedClient *SSHClientContext, iface *IfVethPair) (*IfVethPair, error) {
-	cmd := fmt.Sprintf("sudo ip link add %s type veth peer name %s", iface.Local.Name, iface.Peer.Name)
+	cmd := fmt.Sprintf("sudo ip link add %s%s type veth peer name %s%s", iface.Local.Name, iface.Local.netnsOption(), iface.Peer.Name, iface.Peer.netnsOption())
 	_, err := connectedClient.ExecuteCommand(cmd)
 	if err != nil {
 		return nil, err
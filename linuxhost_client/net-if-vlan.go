@@ -15,7 +15,7 @@ func (m *IfVlan) GetCommon() *IfCommon {
 }
 
 func CreateIfVlan(connectedClient *SSHClientContext, iface *IfVlan) (*IfVlan, error) {
-	cmd := fmt.Sprintf("sudo ip link add link %s name %s type vlan id %d", iface.Parent, iface.Name, iface.Vid)
+	cmd := fmt.Sprintf("sudo ip link add link %s name %s%s type vlan id %d", iface.Parent, iface.Name, iface.netnsOption(), iface.Vid)
 	result, err := connectedClient.ExecuteCommand(cmd)
 	if err != nil {
 		return nil, err
@@ -6,6 +6,20 @@ type IfVxlan struct {
 	IfCommon
 	Vni  uint32
 	Port uint32
+
+	Local  string
+	Remote string
+	Group  string
+	Dev    string
+	// Ttl of 0 means auto, Tos of 1 means inherit
+	Ttl        uint32
+	Tos        uint32
+	Learning   bool
+	SrcPortMin uint32
+	SrcPortMax uint32
+	// UdpCsum nil leaves the kernel default
+	UdpCsum  *bool
+	External bool
 }
 
 var _ IsIf = &IfVxlan{}
@@ -14,8 +28,66 @@ func (m *IfVxlan) GetCommon() *IfCommon {
 	return &m.IfCommon
 }
 
+// VxlanInfo holds the vxlan settings reported by `ip -d`
+type VxlanInfo struct {
+	Local      string
+	Remote     string
+	Group      string
+	Dev        string
+	Ttl        uint32
+	Tos        uint32
+	Learning   bool
+	SrcPortMin uint32
+	SrcPortMax uint32
+	UdpCsum    bool
+	External   bool
+}
+
+func (m *IfVxlan) vxlanOptions() string {
+	options := ""
+	if m.External {
+		options = options + " external"
+	}
+	if m.Local != "" {
+		options = options + fmt.Sprintf(" local %s", m.Local)
+	}
+	if m.Remote != "" {
+		options = options + fmt.Sprintf(" remote %s", m.Remote)
+	}
+	if m.Group != "" {
+		options = options + fmt.Sprintf(" group %s", m.Group)
+	}
+	if m.Dev != "" {
+		options = options + fmt.Sprintf(" dev %s", m.Dev)
+	}
+	if m.Ttl != 0 {
+		options = options + fmt.Sprintf(" ttl %d", m.Ttl)
+	}
+	if m.Tos == 1 {
+		options = options + " tos inherit"
+	} else if m.Tos != 0 {
+		options = options + fmt.Sprintf(" tos %d", m.Tos)
+	}
+	if m.Learning {
+		options = options + " learning"
+	} else {
+		options = options + " nolearning"
+	}
+	if m.SrcPortMin != 0 || m.SrcPortMax != 0 {
+		options = options + fmt.Sprintf(" srcport %d %d", m.SrcPortMin, m.SrcPortMax)
+	}
+	if m.UdpCsum != nil {
+		if *m.UdpCsum {
+			options = options + " udpcsum"
+		} else {
+			options = options + " noudpcsum"
+		}
+	}
+	return options
+}
+
 func CreateIfVXLAN(connectedClient *SSHClientContext, iface *IfVxlan) (*IfVxlan, error) {
-	cmd := fmt.Sprintf("sudo ip link add %s type vxlan id %d dstport %d", iface.Name, iface.Vni, iface.Port)
+	cmd := fmt.Sprintf("sudo ip link add %s%s type vxlan id %d dstport %d%s", iface.Name, iface.netnsOption(), iface.Vni, iface.Port, iface.vxlanOptions())
 	fmt.Println("DO CMD: " + cmd)
 	result, err := connectedClient.ExecuteCommand(cmd)
 	if err != nil {
@@ -0,0 +1,189 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type IfWireguard struct {
+	IfCommon
+	ListenPort uint32
+	FwMark     uint32
+	// PrivateKey is generated on the host when empty
+	PrivateKey string
+}
+
+var _ IsIf = &IfWireguard{}
+
+func (m *IfWireguard) GetCommon() *IfCommon {
+	return &m.IfCommon
+}
+
+type WireguardPeer struct {
+	Interface           string
+	Netns               string
+	PublicKey           string
+	PresharedKey        string
+	Endpoint            string
+	AllowedIPs          []string
+	PersistentKeepalive uint32
+	LatestHandshake     int64
+	TransferRx          int64
+	TransferTx          int64
+}
+
+// WireguardDump is the parsed output of `wg show <if> dump`
+type WireguardDump struct {
+	PrivateKey string
+	PublicKey  string
+	ListenPort uint32
+	FwMark     uint32
+	Peers      []WireguardPeer
+}
+
+func (d *WireguardDump) GetPeer(publicKey string) *WireguardPeer {
+	for i := range d.Peers {
+		if d.Peers[i].PublicKey == publicKey {
+			return &d.Peers[i]
+		}
+	}
+	return nil
+}
+
+func CreateIfWireguard(connectedClient *SSHClientContext, iface *IfWireguard) (*IfWireguard, error) {
+	cmd := fmt.Sprintf("sudo ip link add %s%s type wireguard", iface.Name, iface.netnsOption())
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Println(result)
+	if err := IfSetCommon(connectedClient, iface); err != nil {
+		return nil, *err
+	}
+	if err := IfSetWireguard(connectedClient, iface); err != nil {
+		return nil, err
+	}
+	return iface, nil
+}
+
+// IfSetWireguard applies the key, listen port and fwmark. A given key is
+// written to the stdin of the session so it doesn't show up in the process list.
+func IfSetWireguard(connectedClient *SSHClientContext, iface *IfWireguard) error {
+	cmd := fmt.Sprintf("%s set %s private-key /dev/stdin fwmark %d", NetnsCommand(iface.Netns, "wg"), iface.Name, iface.FwMark)
+	if iface.PrivateKey == "" {
+		cmd = "wg genkey | " + cmd
+	}
+	if iface.ListenPort != 0 {
+		cmd = cmd + fmt.Sprintf(" listen-port %d", iface.ListenPort)
+	}
+	result, err := connectedClient.ExecuteCommandWithInput(cmd, iface.PrivateKey)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+// SetWireguardPeer adds or updates the peer, like the private key the
+// preshared key is written to the stdin of the session
+func SetWireguardPeer(connectedClient *SSHClientContext, peer *WireguardPeer) error {
+	pskSource := "/dev/null"
+	if peer.PresharedKey != "" {
+		pskSource = "/dev/stdin"
+	}
+	cmd := fmt.Sprintf("%s set %s peer %s preshared-key %s persistent-keepalive %d allowed-ips '%s'",
+		NetnsCommand(peer.Netns, "wg"), peer.Interface, peer.PublicKey, pskSource, peer.PersistentKeepalive, strings.Join(peer.AllowedIPs, ","))
+	if peer.Endpoint != "" {
+		cmd = cmd + fmt.Sprintf(" endpoint %s", peer.Endpoint)
+	}
+	result, err := connectedClient.ExecuteCommandWithInput(cmd, peer.PresharedKey)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+func DeleteWireguardPeer(connectedClient *SSHClientContext, peer *WireguardPeer) error {
+	cmd := fmt.Sprintf("%s set %s peer %s remove", NetnsCommand(peer.Netns, "wg"), peer.Interface, peer.PublicKey)
+	_, err := connectedClient.ExecuteCommand(cmd)
+	return err
+}
+
+func ReadWireguard(connectedClient *SSHClientContext, name string) (*WireguardDump, error) {
+	return ReadWireguardInNetns(connectedClient, "", name)
+}
+
+func ReadWireguardInNetns(connectedClient *SSHClientContext, netns string, name string) (*WireguardDump, error) {
+	cmd := fmt.Sprintf("%s show %s dump", NetnsCommand(netns, "wg"), name)
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWireguardDump(name, result), nil
+}
+
+func wgOptional(value string) string {
+	if value == "(none)" || value == "off" {
+		return ""
+	}
+	return value
+}
+
+func wgUint32(value string) uint32 {
+	v, err := strconv.ParseUint(wgOptional(value), 0, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(v)
+}
+
+func wgInt64(value string) int64 {
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// ParseWireguardDump parses `wg show <if> dump`. The first line describes the
+// interface, every following line is a peer. Fields are tab separated.
+func ParseWireguardDump(name string, stdout string) *WireguardDump {
+	var dump *WireguardDump
+	LineMatcher(stdout, func(line string) {
+		fields := strings.Split(line, "\t")
+		if dump == nil {
+			if len(fields) != 4 {
+				return
+			}
+			dump = &WireguardDump{
+				PrivateKey: wgOptional(fields[0]),
+				PublicKey:  wgOptional(fields[1]),
+				ListenPort: wgUint32(fields[2]),
+				FwMark:     wgUint32(fields[3]),
+				Peers:      []WireguardPeer{},
+			}
+			return
+		}
+		if len(fields) != 8 {
+			return
+		}
+		allowedIPs := []string{}
+		if ips := wgOptional(fields[3]); ips != "" {
+			allowedIPs = strings.Split(ips, ",")
+		}
+		dump.Peers = append(dump.Peers, WireguardPeer{
+			Interface:           name,
+			PublicKey:           fields[0],
+			PresharedKey:        wgOptional(fields[1]),
+			Endpoint:            wgOptional(fields[2]),
+			AllowedIPs:          allowedIPs,
+			LatestHandshake:     wgInt64(fields[4]),
+			TransferRx:          wgInt64(fields[5]),
+			TransferTx:          wgInt64(fields[6]),
+			PersistentKeepalive: wgUint32(fields[7]),
+		})
+	})
+	return dump
+}
@@ -0,0 +1,42 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+var wgDumpOutput string = "eCtXsJZ27+4PbhDkHnB923tkUn2Gj59wZw5wFA75MnU=\tfzGx27Fo/YiQEjdVe7ySqrFWvCUKMtJHGjvc8VeiiGI=\t51820\t0xca6c\n" +
+	"jTBbQ3cF/yqa2wDsQjBAJhG0xtHmzR9qUAq4JyKUeBw=\t(none)\t192.0.2.10:51820\t10.0.0.2/32,10.1.0.0/24\t1700000000\t1024\t2048\t25\n" +
+	"n+n6hDx6sg7bjH7Sj3ChKvI1uB9N8k3gWUSdyM1CXgs=\tQmVZcmYyR0h2d1BkYnJEQ0FCN3lkQ2F5ZjBLQVBIMD0=\t(none)\t(none)\t0\t0\t0\toff\n"
+
+func TestParseWireguardDump(t *testing.T) {
+	dump := ParseWireguardDump("wg0", wgDumpOutput)
+	if dump == nil {
+		t.Fatalf("Expected a parsed dump, got nil")
+	}
+	if dump.PublicKey != "fzGx27Fo/YiQEjdVe7ySqrFWvCUKMtJHGjvc8VeiiGI=" {
+		t.Errorf("Unexpected public key %s", dump.PublicKey)
+	}
+	if dump.ListenPort != 51820 {
+		t.Errorf("Expected listen port 51820, got %d", dump.ListenPort)
+	}
+	if dump.FwMark != 0xca6c {
+		t.Errorf("Expected fwmark 0xca6c, got %d", dump.FwMark)
+	}
+	if len(dump.Peers) != 2 {
+		t.Fatalf("There should be 2 peers present, got %d", len(dump.Peers))
+	}
+	first := dump.GetPeer("jTBbQ3cF/yqa2wDsQjBAJhG0xtHmzR9qUAq4JyKUeBw=")
+	if first == nil {
+		t.Fatalf("Expected to find the first peer")
+	}
+	if first.PresharedKey != "" || first.Endpoint != "192.0.2.10:51820" || first.PersistentKeepalive != 25 {
+		t.Errorf("Unexpected first peer %+v", first)
+	}
+	if len(first.AllowedIPs) != 2 || first.AllowedIPs[1] != "10.1.0.0/24" {
+		t.Errorf("Unexpected allowed IPs %v", first.AllowedIPs)
+	}
+	second := dump.Peers[1]
+	if second.PresharedKey == "" || second.Endpoint != "" || len(second.AllowedIPs) != 0 || second.PersistentKeepalive != 0 {
+		t.Errorf("Unexpected second peer %+v", second)
+	}
+}
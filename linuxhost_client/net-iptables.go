@@ -0,0 +1,116 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// IptablesTables are the tables of iptables
+var IptablesTables = []string{"filter", "nat", "mangle", "raw", "security"}
+
+// IptablesRule is a rule of the provider, tagged with Comment by the comment
+// match. Spec are the iptables arguments of the rule in shell syntax, e.g.
+// `-p tcp --dport 22 -j ACCEPT`. Position counts from 1, 0 appends the rule.
+type IptablesRule struct {
+	Table    string
+	Chain    string
+	Spec     string
+	Comment  string
+	Position int
+	IPv6     bool
+}
+
+func (rule *IptablesRule) command() string {
+	if rule.IPv6 {
+		return "sudo ip6tables -w -t " + shellQuote(rule.Table)
+	}
+	return "sudo iptables -w -t " + shellQuote(rule.Table)
+}
+
+// specWithComment is the spec with the comment match, always last so -C
+// compares the matches in the order they were added
+func (rule *IptablesRule) specWithComment() string {
+	return fmt.Sprintf("%s -m comment --comment %s", rule.Spec, shellQuote(rule.Comment))
+}
+
+// AddIptablesRule inserts the rule at its position or appends it
+func AddIptablesRule(connectedClient *SSHClientContext, rule *IptablesRule) error {
+	cmd := fmt.Sprintf("%s -A %s %s", rule.command(), shellQuote(rule.Chain), rule.specWithComment())
+	if rule.Position > 0 {
+		cmd = fmt.Sprintf("%s -I %s %d %s", rule.command(), shellQuote(rule.Chain), rule.Position, rule.specWithComment())
+	}
+	_, err := connectedClient.ExecuteCommand(cmd)
+	return err
+}
+
+// ReplaceIptablesRule replaces the rule at position with rule
+func ReplaceIptablesRule(connectedClient *SSHClientContext, rule *IptablesRule, position int) error {
+	_, err := connectedClient.ExecuteCommand(fmt.Sprintf("%s -R %s %d %s", rule.command(), shellQuote(rule.Chain), position, rule.specWithComment()))
+	return err
+}
+
+// DeleteIptablesRule deletes the rule at position of the chain of rule
+func DeleteIptablesRule(connectedClient *SSHClientContext, rule *IptablesRule, position int) error {
+	_, err := connectedClient.ExecuteCommand(fmt.Sprintf("%s -D %s %d", rule.command(), shellQuote(rule.Chain), position))
+	return err
+}
+
+// CheckIptablesRule reports whether the chain has the rule exactly as
+// specified
+func CheckIptablesRule(connectedClient *SSHClientContext, rule *IptablesRule) (bool, error) {
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("%s -C %s %s >/dev/null 2>&1 && echo present || echo absent", rule.command(), shellQuote(rule.Chain), rule.specWithComment()))
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(result) == "present", nil
+}
+
+// ReadIptablesRule finds the rule with comment in iptables-save of the table,
+// nil if it doesn't exist
+func ReadIptablesRule(connectedClient *SSHClientContext, table string, comment string, ipv6 bool) (*IptablesRule, error) {
+	save := "iptables-save"
+	if ipv6 {
+		save = "ip6tables-save"
+	}
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo %s -t %s", save, shellQuote(table)))
+	if err != nil {
+		return nil, err
+	}
+	rule := ParseIptablesSave(result, comment)
+	if rule != nil {
+		rule.Table = table
+		rule.IPv6 = ipv6
+	}
+	return rule, nil
+}
+
+// ParseIptablesSave finds the rule with comment in the output of
+// iptables-save. Its spec is the normalized one of iptables-save, without
+// the comment match.
+func ParseIptablesSave(output string, comment string) *IptablesRule {
+	// iptables-save only quotes comments with spaces, some versions all
+	commentMatch := regexp.MustCompile(`\s+-m comment --comment (?:"` + regexp.QuoteMeta(comment) + `"|` + regexp.QuoteMeta(comment) + `)(\s|$)`)
+	positions := map[string]int{}
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		rest, found := strings.CutPrefix(line, "-A ")
+		if !found {
+			continue
+		}
+		chain, spec, _ := strings.Cut(rest, " ")
+		positions[chain]++
+		match := commentMatch.FindStringSubmatchIndex(" " + spec)
+		if match == nil {
+			continue
+		}
+		spec = " " + spec
+		return &IptablesRule{
+			Chain:    chain,
+			Spec:     strings.TrimSpace(spec[:match[0]] + spec[match[2]:]),
+			Comment:  comment,
+			Position: positions[chain],
+		}
+	}
+	return nil
+}
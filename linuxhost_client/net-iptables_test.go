@@ -0,0 +1,39 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+var iptablesSaveOutput string = `# Generated by iptables-save v1.8.7 on Mon Oct 19 10:00:00 2026
+*filter
+:INPUT DROP [0:0]
+:FORWARD ACCEPT [0:0]
+:OUTPUT ACCEPT [0:0]
+-A INPUT -m state --state RELATED,ESTABLISHED -j ACCEPT
+-A INPUT -p tcp -m tcp --dport 22 -m comment --comment "linuxhost:0011223344556677" -j ACCEPT
+-A FORWARD -s 10.0.0.0/8 -m comment --comment linuxhost:8899aabbccddeeff -j ACCEPT
+-A INPUT -m comment --comment "managed by someone else" -j LOG
+COMMIT
+# Completed on Mon Oct 19 10:00:00 2026
+`
+
+func TestParseIptablesSave(t *testing.T) {
+	ssh := ParseIptablesSave(iptablesSaveOutput, "linuxhost:0011223344556677")
+	if ssh == nil || ssh.Chain != "INPUT" || ssh.Position != 2 || ssh.Spec != "-p tcp -m tcp --dport 22 -j ACCEPT" {
+		t.Errorf("Unexpected rule %+v", ssh)
+	}
+	forward := ParseIptablesSave(iptablesSaveOutput, "linuxhost:8899aabbccddeeff")
+	if forward == nil || forward.Chain != "FORWARD" || forward.Position != 1 || forward.Spec != "-s 10.0.0.0/8 -j ACCEPT" {
+		t.Errorf("Expected the unquoted comment to match, got %+v", forward)
+	}
+	if rule := ParseIptablesSave(iptablesSaveOutput, "linuxhost:00112233"); rule != nil {
+		t.Errorf("Expected no rule for a prefix of a comment, got %+v", rule)
+	}
+}
+
+func TestIptablesRuleCommands(t *testing.T) {
+	rule := &IptablesRule{Table: "nat", Chain: "POSTROUTING", Spec: "-o eth0 -j MASQUERADE", Comment: "linuxhost:00", IPv6: true}
+	if cmd := rule.command() + " " + rule.specWithComment(); cmd != "sudo ip6tables -w -t 'nat' -o eth0 -j MASQUERADE -m comment --comment 'linuxhost:00'" {
+		t.Errorf("Unexpected command %s", cmd)
+	}
+}
@@ -0,0 +1,125 @@
+package linuxhost_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Neighbor is an ARP or NDP entry. Proxy entries have no Lladdr or State.
+type Neighbor struct {
+	Address string
+	Lladdr  string
+	Dev     string
+	// State is "permanent", "noarp" or "reachable"; dynamic entries also
+	// report "stale", "delay" or "probe"
+	State string
+	Proxy bool
+}
+
+type neighborJson struct {
+	Dst    string   `json:"dst"`
+	Dev    string   `json:"dev"`
+	Lladdr string   `json:"lladdr"`
+	State  []string `json:"state"`
+}
+
+func (n *Neighbor) proxyArg() string {
+	if n.Proxy {
+		return " proxy"
+	}
+	return ""
+}
+
+// SetNeighbor creates or replaces the entry
+func SetNeighbor(connectedClient *SSHClientContext, neighbor *Neighbor) error {
+	cmd := fmt.Sprintf("sudo ip neigh replace%s %s dev %s", neighbor.proxyArg(), neighbor.Address, neighbor.Dev)
+	if !neighbor.Proxy {
+		cmd = cmd + fmt.Sprintf(" lladdr %s nud %s", neighbor.Lladdr, neighbor.State)
+	}
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+func DeleteNeighbor(connectedClient *SSHClientContext, neighbor *Neighbor) error {
+	cmd := fmt.Sprintf("sudo ip neigh del%s %s dev %s", neighbor.proxyArg(), neighbor.Address, neighbor.Dev)
+	_, err := connectedClient.ExecuteCommand(cmd)
+	return err
+}
+
+// ReadNeighbors returns the IPv4 and IPv6 entries of dev, or its proxy entries
+const neighMissing = "### missing"
+
+// ReadNeighbors lists the neighbors of dev, they're nil if the interface
+// doesn't exist
+func ReadNeighbors(connectedClient *SSHClientContext, dev string, proxy bool) ([]Neighbor, error) {
+	name := shellQuote(dev)
+	cmd := fmt.Sprintf("ip -json neigh show dev %s", name)
+	if proxy {
+		cmd = fmt.Sprintf("ip -json neigh show proxy dev %s", name)
+	}
+	cmd = fmt.Sprintf("[ -e /sys/class/net/%s ] || { echo '%s'; exit 0; }; ", name, neighMissing) + cmd
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return nil, err
+	}
+	neighbors, err := ParseNeighbors(result, proxy)
+	if err != nil {
+		return nil, err
+	}
+	for i := range neighbors {
+		if neighbors[i].Dev == "" {
+			neighbors[i].Dev = dev
+		}
+	}
+	return neighbors, nil
+}
+
+// ParseNeighbors parses the output of `ip -json neigh show`
+func ParseNeighbors(output string, proxy bool) ([]Neighbor, error) {
+	if strings.TrimSpace(output) == neighMissing {
+		return nil, nil
+	}
+	entries := []neighborJson{}
+	if strings.TrimSpace(output) == "" {
+		return []Neighbor{}, nil
+	}
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse neighbor output: %w", err)
+	}
+	neighbors := []Neighbor{}
+	for _, entry := range entries {
+		neighbor := Neighbor{
+			Address: entry.Dst,
+			Lladdr:  strings.ToLower(entry.Lladdr),
+			Dev:     entry.Dev,
+			Proxy:   proxy,
+		}
+		if len(entry.State) > 0 {
+			neighbor.State = strings.ToLower(entry.State[0])
+		}
+		neighbors = append(neighbors, neighbor)
+	}
+	return neighbors, nil
+}
+
+// FindNeighbor returns the entry for the address and device of target, or nil.
+// Addresses are compared as IPs so IPv6 notation differences don't matter.
+func FindNeighbor(neighbors []Neighbor, target *Neighbor) *Neighbor {
+	targetIP := net.ParseIP(target.Address)
+	for i := range neighbors {
+		neighbor := &neighbors[i]
+		if neighbor.Dev != target.Dev || neighbor.Proxy != target.Proxy {
+			continue
+		}
+		if (targetIP != nil && targetIP.Equal(net.ParseIP(neighbor.Address))) || neighbor.Address == target.Address {
+			return neighbor
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+var neighborOutput string = `[{"dst":"192.0.2.254","lladdr":"52:54:00:AA:BB:CC","state":["PERMANENT"]},{"dst":"192.0.2.10","lladdr":"52:54:00:aa:bb:cd","state":["STALE"]},{"dst":"2001:db8::1","lladdr":"52:54:00:aa:bb:ce","router":null,"state":["NOARP"]},{"dst":"fe80::1","lladdr":"52:54:00:aa:bb:cf","state":["REACHABLE"]}]`
+
+func TestParseNeighbors(t *testing.T) {
+	neighbors, err := ParseNeighbors(neighborOutput, false)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(neighbors) != 4 {
+		t.Errorf("There should be 4 neighbors present, got %d", len(neighbors))
+	}
+	for i := range neighbors {
+		neighbors[i].Dev = "eth0"
+	}
+
+	gateway := FindNeighbor(neighbors, &Neighbor{Address: "192.0.2.254", Dev: "eth0"})
+	if gateway == nil || gateway.State != "permanent" || gateway.Lladdr != "52:54:00:aa:bb:cc" {
+		t.Errorf("Expected a permanent gateway entry, got %+v", gateway)
+	}
+
+	v6 := FindNeighbor(neighbors, &Neighbor{Address: "2001:0db8:0::1", Dev: "eth0"})
+	if v6 == nil || v6.State != "noarp" {
+		t.Errorf("Expected the IPv6 entry to match regardless of notation, got %+v", v6)
+	}
+
+	if FindNeighbor(neighbors, &Neighbor{Address: "192.0.2.254", Dev: "eth1"}) != nil {
+		t.Errorf("Expected no entry on eth1")
+	}
+	if FindNeighbor(neighbors, &Neighbor{Address: "192.0.2.254", Dev: "eth0", Proxy: true}) != nil {
+		t.Errorf("Expected no proxy entry")
+	}
+}
+
+func TestParseNeighborsProxy(t *testing.T) {
+	if missing, err := ParseNeighbors(neighMissing+"\n", true); err != nil || missing != nil {
+		t.Errorf("Expected no neighbors for a missing device, got %v, %v", missing, err)
+	}
+
+	neighbors, err := ParseNeighbors(`[{"dst":"192.0.2.50","dev":"eth0","proxy":null}]`, true)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	proxy := FindNeighbor(neighbors, &Neighbor{Address: "192.0.2.50", Dev: "eth0", Proxy: true})
+	if proxy == nil || proxy.Lladdr != "" {
+		t.Errorf("Expected a proxy entry, got %+v", proxy)
+	}
+}
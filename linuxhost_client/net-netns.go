@@ -0,0 +1,102 @@
+package linuxhost_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Netns is a named network namespace below /run/netns. Nsid is nil if unset.
+type Netns struct {
+	Name       string
+	Nsid       *uint32
+	Interfaces []string
+}
+
+type netnsJson struct {
+	Name string  `json:"name"`
+	Id   *uint32 `json:"id"`
+}
+
+type netnsLinkJson struct {
+	Ifname string `json:"ifname"`
+}
+
+// CreateNetns adds the namespace and brings its loopback up
+func CreateNetns(connectedClient *SSHClientContext, netns *Netns) error {
+	cmd := fmt.Sprintf("sudo ip netns add %s && sudo ip -n %s link set lo up", netns.Name, netns.Name)
+	if netns.Nsid != nil {
+		cmd = cmd + fmt.Sprintf(" && sudo ip netns set %s %d", netns.Name, *netns.Nsid)
+	}
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return err
+	}
+	fmt.Println(result)
+	return nil
+}
+
+func DeleteNetns(connectedClient *SSHClientContext, name string) error {
+	_, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo ip netns del %s", name))
+	return err
+}
+
+// ReadNetns returns the namespace with its interfaces, or nil if it doesn't exist
+func ReadNetns(connectedClient *SSHClientContext, name string) (*Netns, error) {
+	result, err := connectedClient.ExecuteCommand("ip -json netns list")
+	if err != nil {
+		return nil, err
+	}
+	namespaces, err := ParseNetnsList(result)
+	if err != nil {
+		return nil, err
+	}
+	var found *Netns
+	for i := range namespaces {
+		if namespaces[i].Name == name {
+			found = &namespaces[i]
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+	links, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo ip -n %s -json link show", name))
+	if err != nil {
+		return nil, err
+	}
+	found.Interfaces, err = ParseNetnsLinks(links)
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// ParseNetnsList parses the output of `ip -json netns list`, which is empty
+// when there are no namespaces
+func ParseNetnsList(output string) ([]Netns, error) {
+	namespaces := []Netns{}
+	if strings.TrimSpace(output) == "" {
+		return namespaces, nil
+	}
+	entries := []netnsJson{}
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse netns output: %w", err)
+	}
+	for _, entry := range entries {
+		namespaces = append(namespaces, Netns{Name: entry.Name, Nsid: entry.Id})
+	}
+	return namespaces, nil
+}
+
+// ParseNetnsLinks returns the interface names of `ip -json link show`
+func ParseNetnsLinks(output string) ([]string, error) {
+	entries := []netnsLinkJson{}
+	if err := json.Unmarshal([]byte(output), &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse link output: %w", err)
+	}
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Ifname)
+	}
+	return names, nil
+}
@@ -0,0 +1,36 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+func TestParseNetnsList(t *testing.T) {
+	namespaces, err := ParseNetnsList(`[{"name":"tenant2"},{"name":"tenant1","id":0}]`)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(namespaces) != 2 {
+		t.Fatalf("There should be 2 namespaces present, got %d", len(namespaces))
+	}
+	if namespaces[0].Name != "tenant2" || namespaces[0].Nsid != nil {
+		t.Errorf("Expected tenant2 without nsid, got %+v", namespaces[0])
+	}
+	if namespaces[1].Nsid == nil || *namespaces[1].Nsid != 0 {
+		t.Errorf("Expected tenant1 with nsid 0, got %+v", namespaces[1])
+	}
+
+	empty, err := ParseNetnsList("")
+	if err != nil || len(empty) != 0 {
+		t.Errorf("Expected no namespaces for empty output, got %v %v", empty, err)
+	}
+}
+
+func TestParseNetnsLinks(t *testing.T) {
+	names, err := ParseNetnsLinks(`[{"ifindex":1,"ifname":"lo","flags":["LOOPBACK","UP","LOWER_UP"]},{"ifindex":5,"link_index":6,"ifname":"veth1","flags":["BROADCAST","MULTICAST"],"link_netnsid":0}]`)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(names) != 2 || names[0] != "lo" || names[1] != "veth1" {
+		t.Errorf("Unexpected interfaces %v", names)
+	}
+}
@@ -0,0 +1,251 @@
+package linuxhost_client
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NftFamilies are the address families of nftables tables
+var NftFamilies = []string{"ip", "ip6", "inet", "arp", "bridge", "netdev"}
+
+type NftTable struct {
+	Family string `json:"family"`
+	Name   string `json:"name"`
+	Handle int64  `json:"handle"`
+}
+
+// NftChain is a regular chain or, if Type is set, a base chain attached to
+// Hook. Device is only used by netdev ingress and egress chains.
+type NftChain struct {
+	Family   string `json:"family"`
+	Table    string `json:"table"`
+	Name     string `json:"name"`
+	Handle   int64  `json:"handle"`
+	Type     string `json:"type,omitempty"`
+	Hook     string `json:"hook,omitempty"`
+	Priority *int32 `json:"prio,omitempty"`
+	Policy   string `json:"policy,omitempty"`
+	Device   string `json:"-"`
+}
+
+// NftRule is a rule as listed by nft, Expr is its expression in the JSON
+// syntax which is compared to detect changes
+type NftRule struct {
+	Family  string          `json:"family"`
+	Table   string          `json:"table"`
+	Chain   string          `json:"chain"`
+	Handle  int64           `json:"handle"`
+	Comment string          `json:"comment"`
+	Expr    json.RawMessage `json:"expr"`
+}
+
+type NftRuleset struct {
+	Tables []NftTable
+	Chains []NftChain
+	Rules  []NftRule
+}
+
+type nftChainJson struct {
+	NftChain
+	Dev json.RawMessage `json:"dev"`
+}
+
+type nftRulesetJson struct {
+	Nftables []struct {
+		Table *NftTable     `json:"table"`
+		Chain *nftChainJson `json:"chain"`
+		Rule  *NftRule      `json:"rule"`
+	} `json:"nftables"`
+}
+
+// nft joins its arguments, passing the command as one argument keeps the
+// shell away from braces, semicolons and quotes
+func nft(connectedClient *SSHClientContext, command string) error {
+	_, err := connectedClient.ExecuteCommand("sudo nft " + shellQuote(command))
+	return err
+}
+
+// ReadNftRuleset lists the whole ruleset, nft has no JSON listing of single
+// objects that doesn't fail when they're missing
+func ReadNftRuleset(connectedClient *SSHClientContext) (*NftRuleset, error) {
+	result, err := connectedClient.ExecuteCommand("sudo nft -j list ruleset")
+	if err != nil {
+		return nil, err
+	}
+	return ParseNftRuleset(result)
+}
+
+// ParseNftRuleset parses the output of `nft -j list ruleset`
+func ParseNftRuleset(output string) (*NftRuleset, error) {
+	ruleset := &NftRuleset{Tables: []NftTable{}, Chains: []NftChain{}, Rules: []NftRule{}}
+	if strings.TrimSpace(output) == "" {
+		return ruleset, nil
+	}
+	var parsed nftRulesetJson
+	if err := json.Unmarshal([]byte(output), &parsed); err != nil {
+		return nil, fmt.Errorf("failed to parse nft ruleset: %w", err)
+	}
+	for _, object := range parsed.Nftables {
+		switch {
+		case object.Table != nil:
+			ruleset.Tables = append(ruleset.Tables, *object.Table)
+		case object.Chain != nil:
+			chain := object.Chain.NftChain
+			// A single device is a string, several are a list
+			var devices []string
+			if err := json.Unmarshal(object.Chain.Dev, &chain.Device); err != nil && json.Unmarshal(object.Chain.Dev, &devices) == nil && len(devices) > 0 {
+				chain.Device = devices[0]
+			}
+			ruleset.Chains = append(ruleset.Chains, chain)
+		case object.Rule != nil:
+			ruleset.Rules = append(ruleset.Rules, *object.Rule)
+		}
+	}
+	return ruleset, nil
+}
+
+func (r *NftRuleset) GetTable(family string, name string) *NftTable {
+	for i := range r.Tables {
+		if r.Tables[i].Family == family && r.Tables[i].Name == name {
+			return &r.Tables[i]
+		}
+	}
+	return nil
+}
+
+func (r *NftRuleset) GetChain(family string, table string, name string) *NftChain {
+	for i := range r.Chains {
+		if r.Chains[i].Family == family && r.Chains[i].Table == table && r.Chains[i].Name == name {
+			return &r.Chains[i]
+		}
+	}
+	return nil
+}
+
+// GetRule finds a rule of the provider by its comment, wherever it moved to
+func (r *NftRuleset) GetRule(family string, table string, comment string) *NftRule {
+	for i := range r.Rules {
+		if r.Rules[i].Family == family && r.Rules[i].Table == table && r.Rules[i].Comment == comment {
+			return &r.Rules[i]
+		}
+	}
+	return nil
+}
+
+func CreateNftTable(connectedClient *SSHClientContext, family string, name string) error {
+	return nft(connectedClient, fmt.Sprintf("add table %s %s", family, name))
+}
+
+func DeleteNftTable(connectedClient *SSHClientContext, family string, name string) error {
+	return nft(connectedClient, fmt.Sprintf("delete table %s %s", family, name))
+}
+
+// NftChainSpec renders the chain for `nft add chain`
+func NftChainSpec(chain *NftChain) string {
+	spec := fmt.Sprintf("%s %s %s", chain.Family, chain.Table, chain.Name)
+	if chain.Type == "" {
+		return spec
+	}
+	spec = spec + fmt.Sprintf(" { type %s hook %s", chain.Type, chain.Hook)
+	if chain.Device != "" {
+		spec = spec + fmt.Sprintf(" device %q", chain.Device)
+	}
+	priority := int32(0)
+	if chain.Priority != nil {
+		priority = *chain.Priority
+	}
+	spec = spec + fmt.Sprintf(" priority %d;", priority)
+	if chain.Policy != "" {
+		spec = spec + fmt.Sprintf(" policy %s;", chain.Policy)
+	}
+	return spec + " }"
+}
+
+// SetNftChain adds the chain, adding an existing base chain again updates
+// its policy
+func SetNftChain(connectedClient *SSHClientContext, chain *NftChain) error {
+	return nft(connectedClient, "add chain "+NftChainSpec(chain))
+}
+
+// DeleteNftChain flushes the chain first, nft refuses to delete chains with
+// rules
+func DeleteNftChain(connectedClient *SSHClientContext, chain *NftChain) error {
+	name := fmt.Sprintf("%s %s %s", chain.Family, chain.Table, chain.Name)
+	if err := nft(connectedClient, "flush chain "+name); err != nil {
+		return err
+	}
+	return nft(connectedClient, "delete chain "+name)
+}
+
+// NewRuleComment returns a random comment identifying a firewall rule of the
+// provider
+func NewRuleComment() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return "linuxhost:" + hex.EncodeToString(id), nil
+}
+
+// AddNftRule appends the rule to the chain, or inserts it at the start
+func AddNftRule(connectedClient *SSHClientContext, family string, table string, chain string, rule string, comment string, insert bool) error {
+	verb := "add"
+	if insert {
+		verb = "insert"
+	}
+	return nft(connectedClient, fmt.Sprintf("%s rule %s %s %s %s comment %q", verb, family, table, chain, rule, comment))
+}
+
+// ReplaceNftRule changes the rule with handle in place, keeping its position
+func ReplaceNftRule(connectedClient *SSHClientContext, family string, table string, chain string, handle int64, rule string, comment string) error {
+	return nft(connectedClient, fmt.Sprintf("replace rule %s %s %s handle %d %s comment %q", family, table, chain, handle, rule, comment))
+}
+
+func DeleteNftRule(connectedClient *SSHClientContext, family string, table string, chain string, handle int64) error {
+	return nft(connectedClient, fmt.Sprintf("delete rule %s %s %s handle %d", family, table, chain, handle))
+}
+
+// NftExprEqual compares two expressions in the JSON syntax regardless of
+// formatting
+func NftExprEqual(a json.RawMessage, b json.RawMessage) bool {
+	var bufA, bufB bytes.Buffer
+	if json.Compact(&bufA, a) != nil || json.Compact(&bufB, b) != nil {
+		return false
+	}
+	return bufA.String() == bufB.String()
+}
+
+// ReadNftRuleText returns the rule with handle in the nft syntax, without
+// its comment
+func ReadNftRuleText(connectedClient *SSHClientContext, family string, table string, chain string, handle int64) (string, error) {
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo nft -a list chain %s %s %s", family, table, chain))
+	if err != nil {
+		return "", err
+	}
+	return ParseNftRuleText(result, handle), nil
+}
+
+var nftRuleSuffix = regexp.MustCompile(`(\s+comment\s+"[^"]*")?\s+# handle (\d+)$`)
+
+// ParseNftRuleText finds the rule with handle in the output of
+// `nft -a list chain`
+func ParseNftRuleText(output string, handle int64) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		match := nftRuleSuffix.FindStringSubmatchIndex(line)
+		if match == nil || line[match[4]:match[5]] != fmt.Sprint(handle) {
+			continue
+		}
+		// Chains list their own handle too
+		if strings.HasPrefix(line, "chain ") {
+			continue
+		}
+		return line[:match[0]]
+	}
+	return ""
+}
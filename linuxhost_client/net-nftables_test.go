@@ -0,0 +1,74 @@
+package linuxhost_client
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+var nftRulesetOutput string = `{"nftables": [{"metainfo": {"version": "1.0.6", "release_name": "Lester Gooch #5", "json_schema_version": 1}}, {"table": {"family": "inet", "name": "filter", "handle": 1}}, {"chain": {"family": "inet", "table": "filter", "name": "input", "handle": 1, "type": "filter", "hook": "input", "prio": 0, "policy": "drop"}}, {"chain": {"family": "inet", "table": "filter", "name": "ssh", "handle": 2}}, {"rule": {"family": "inet", "table": "filter", "chain": "input", "handle": 4, "comment": "linuxhost:0011223344556677", "expr": [{"match": {"op": "==", "left": {"payload": {"protocol": "tcp", "field": "dport"}}, "right": 22}}, {"accept": null}]}}, {"table": {"family": "netdev", "name": "edge", "handle": 2}}, {"chain": {"family": "netdev", "table": "edge", "name": "ingress", "handle": 1, "type": "filter", "hook": "ingress", "prio": -500, "policy": "accept", "dev": "eth0"}}]}`
+
+func TestParseNftRuleset(t *testing.T) {
+	ruleset, err := ParseNftRuleset(nftRulesetOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if len(ruleset.Tables) != 2 || ruleset.GetTable("inet", "filter") == nil || ruleset.GetTable("ip", "filter") != nil {
+		t.Errorf("Unexpected tables %+v", ruleset.Tables)
+	}
+
+	input := ruleset.GetChain("inet", "filter", "input")
+	if input == nil || input.Type != "filter" || input.Hook != "input" || input.Priority == nil || *input.Priority != 0 || input.Policy != "drop" {
+		t.Errorf("Unexpected input chain %+v", input)
+	}
+	if ssh := ruleset.GetChain("inet", "filter", "ssh"); ssh == nil || ssh.Type != "" || ssh.Priority != nil {
+		t.Errorf("Expected a regular chain, got %+v", ssh)
+	}
+	if ingress := ruleset.GetChain("netdev", "edge", "ingress"); ingress == nil || ingress.Device != "eth0" || *ingress.Priority != -500 {
+		t.Errorf("Unexpected ingress chain %+v", ingress)
+	}
+
+	rule := ruleset.GetRule("inet", "filter", "linuxhost:0011223344556677")
+	if rule == nil || rule.Chain != "input" || rule.Handle != 4 {
+		t.Fatalf("Unexpected rule %+v", rule)
+	}
+	expected := json.RawMessage(`[{"match":{"op":"==","left":{"payload":{"protocol":"tcp","field":"dport"}},"right":22}},{"accept":null}]`)
+	if !NftExprEqual(rule.Expr, expected) {
+		t.Errorf("Expected the expression to equal regardless of formatting, got %s", rule.Expr)
+	}
+
+	empty, err := ParseNftRuleset("")
+	if err != nil || len(empty.Tables) != 0 {
+		t.Errorf("Expected an empty ruleset, got %+v %v", empty, err)
+	}
+}
+
+func TestNftChainSpec(t *testing.T) {
+	priority := int32(-150)
+	spec := NftChainSpec(&NftChain{Family: "ip", Table: "nat", Name: "prerouting", Type: "nat", Hook: "prerouting", Priority: &priority, Policy: "accept"})
+	if spec != "ip nat prerouting { type nat hook prerouting priority -150; policy accept; }" {
+		t.Errorf("Unexpected base chain %s", spec)
+	}
+	if spec := NftChainSpec(&NftChain{Family: "inet", Table: "filter", Name: "ssh"}); spec != "inet filter ssh" {
+		t.Errorf("Unexpected regular chain %s", spec)
+	}
+}
+
+func TestParseNftRuleText(t *testing.T) {
+	output := `table inet filter {
+	chain input { # handle 1
+		type filter hook input priority filter; policy drop;
+		tcp dport 22 accept comment "linuxhost:0011223344556677" # handle 4
+		ip saddr 10.0.0.0/8 counter packets 0 bytes 0 accept # handle 1
+	}
+}
+`
+	if text := ParseNftRuleText(output, 4); text != "tcp dport 22 accept" {
+		t.Errorf("Expected the rule without comment, got %q", text)
+	}
+	if text := ParseNftRuleText(output, 1); text != "ip saddr 10.0.0.0/8 counter packets 0 bytes 0 accept" {
+		t.Errorf("Expected the rule rather than the chain, got %q", text)
+	}
+	if text := ParseNftRuleText(output, 7); text != "" {
+		t.Errorf("Expected no rule, got %q", text)
+	}
+}
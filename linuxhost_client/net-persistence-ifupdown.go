@@ -0,0 +1,360 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const ifupdownDirectory = "/etc/network/interfaces.d"
+
+// ifupdownLocks serializes the changes of bridge-ports per host, bridge
+// members edit the file of their bridge
+var ifupdownLocks hostLocks
+
+var ifupdownUnsafe = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// IfupdownFile is the stanza file of an interface or address. `source-directory`
+// skips names with other characters than letters, digits, - and _.
+func IfupdownFile(name string) string {
+	return fmt.Sprintf("%s/linuxhost-%s", ifupdownDirectory, ifupdownUnsafe.ReplaceAllString(name, "_"))
+}
+
+type ifupdownPersistence struct{}
+
+var _ Persistence = &ifupdownPersistence{}
+
+func onOff(value bool) string {
+	if value {
+		return "on"
+	}
+	return "off"
+}
+
+// seconds formats centiseconds as seconds, keeping the fraction
+func seconds(centiseconds uint32) string {
+	return strconv.FormatFloat(float64(centiseconds)/100, 'f', -1, 64)
+}
+
+// RenderIfupdownIf renders the stanza of an interface in the ifupdown2
+// syntax. ports are the bridge-ports of a bridge.
+func RenderIfupdownIf(ifaceX IsIf, ports []string) (string, error) {
+	iface := ifaceX.GetCommon()
+	options := []string{}
+	set := func(key string, value string) {
+		options = append(options, key+" "+value)
+	}
+
+	switch m := ifaceX.(type) {
+	case *IfBridge:
+		if len(ports) == 0 {
+			set("bridge-ports", "none")
+		} else {
+			set("bridge-ports", strings.Join(ports, " "))
+		}
+		if m.Stp != nil {
+			set("bridge-stp", onOff(*m.Stp))
+		}
+		// ifupdown has the timers in seconds, the bridge in centiseconds
+		timers := []struct {
+			key   string
+			value *uint32
+		}{
+			{"bridge-fd", m.ForwardDelay},
+			{"bridge-hello", m.HelloTime},
+			{"bridge-maxage", m.MaxAge},
+			{"bridge-ageing", m.AgeingTime},
+		}
+		for _, timer := range timers {
+			if timer.value != nil {
+				set(timer.key, seconds(*timer.value))
+			}
+		}
+		if m.Priority != nil {
+			set("bridge-bridgeprio", fmt.Sprint(*m.Priority))
+		}
+		if m.McastSnooping != nil {
+			set("bridge-mcsnoop", yesNo(*m.McastSnooping))
+		}
+		if m.McastQuerier != nil {
+			set("bridge-mcquerier", yesNo(*m.McastQuerier))
+		}
+		set("bridge-vlan-aware", yesNo(m.VlanFiltering))
+		if m.VlanDefaultPvid != nil {
+			set("bridge-pvid", fmt.Sprint(*m.VlanDefaultPvid))
+		}
+		if m.VlanProtocol != "" {
+			set("bridge-vlan-protocol", m.VlanProtocol)
+		}
+	case *IfDummy:
+		set("link-type", "dummy")
+	case *IfVlan:
+		set("vlan-raw-device", m.Parent)
+		set("vlan-id", fmt.Sprint(m.Vid))
+	case *IfVxlan:
+		set("vxlan-id", fmt.Sprint(m.Vni))
+		set("vxlan-port", fmt.Sprint(m.Port))
+		if m.Local != "" {
+			set("vxlan-local-tunnelip", m.Local)
+		}
+		if m.Remote != "" {
+			set("vxlan-remoteip", m.Remote)
+		}
+		if m.Group != "" {
+			set("vxlan-mcastgrp", m.Group)
+		}
+		if m.Dev != "" {
+			set("vxlan-physdev", m.Dev)
+		}
+		if m.Ttl != 0 {
+			set("vxlan-ttl", fmt.Sprint(m.Ttl))
+		}
+		if m.Tos == 1 {
+			set("vxlan-tos", "inherit")
+		} else if m.Tos != 0 {
+			set("vxlan-tos", fmt.Sprint(m.Tos))
+		}
+		set("vxlan-learning", yesNo(m.Learning))
+		if m.SrcPortMin != 0 || m.SrcPortMax != 0 {
+			return "", fmt.Errorf("ifupdown has no option for the source port range of %s", iface.Name)
+		}
+	case *IfTunnel:
+		set("tunnel-mode", m.Kind)
+		if m.Local != "" {
+			set("tunnel-local", m.Local)
+		}
+		set("tunnel-endpoint", m.Remote)
+		if m.Ttl != 0 {
+			set("tunnel-ttl", fmt.Sprint(m.Ttl))
+		}
+		if m.Key != nil {
+			return "", fmt.Errorf("ifupdown has no option for the key of %s", iface.Name)
+		}
+	case *IfVethPeer:
+		set("link-type", "veth")
+		set("veth-peer-name", m.PeerName)
+	default:
+		return "", fmt.Errorf("persisting %s with ifupdown is not supported", iface.Name)
+	}
+
+	if iface.Mac != "" {
+		set("hwaddress", iface.Mac)
+	}
+	if iface.Mtu != 0 {
+		set("mtu", fmt.Sprint(iface.Mtu))
+	}
+	if iface.Alias != "" {
+		// The rest of the line is the alias, quotes would be part of it
+		set("alias", iface.Alias)
+	}
+	if member := iface.BridgeMember; member != nil {
+		if member.Cost != nil {
+			set("bridge-pathcosts", fmt.Sprint(*member.Cost))
+		}
+		if member.Priority != nil {
+			set("bridge-portprios", fmt.Sprint(*member.Priority))
+		}
+		bridgePortFlags := []struct {
+			key   string
+			value *bool
+		}{
+			{"bridge-learning", member.Learning},
+			{"bridge-unicast-flood", member.Flood},
+			{"bridge-multicast-flood", member.McastFlood},
+			{"bridge-arp-nd-suppress", member.NeighSuppress},
+			{"bridge-port-isolation", member.Isolated},
+		}
+		for _, flag := range bridgePortFlags {
+			if flag.value != nil {
+				set(flag.key, onOff(*flag.value))
+			}
+		}
+	}
+
+	stanza := "# Managed by terraform-provider-linuxhost\n"
+	if iface.State != "down" {
+		stanza = stanza + "auto " + iface.Name + "\n"
+	}
+	stanza = stanza + "iface " + iface.Name + "\n"
+	for _, option := range options {
+		stanza = stanza + "    " + option + "\n"
+	}
+	return stanza, nil
+}
+
+// RenderIfupdownAddress renders the stanza adding an address to dev,
+// ifupdown2 merges it with the other stanzas of dev
+func RenderIfupdownAddress(dev string, address string) string {
+	return fmt.Sprintf("# Managed by terraform-provider-linuxhost\niface %s\n    address %s\n", dev, address)
+}
+
+func ifupdownAddressFile(dev string, address string) string {
+	return IfupdownFile(dev + "-address-" + address)
+}
+
+// ParseIfupdownPorts returns the bridge-ports of a stanza
+func ParseIfupdownPorts(stanza string) []string {
+	for _, line := range strings.Split(stanza, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "bridge-ports" {
+			continue
+		}
+		if fields[1] == "none" {
+			return []string{}
+		}
+		return fields[1:]
+	}
+	return []string{}
+}
+
+// IfupdownSetPorts replaces the bridge-ports of a stanza
+func IfupdownSetPorts(stanza string, ports []string) string {
+	value := "none"
+	if len(ports) > 0 {
+		value = strings.Join(ports, " ")
+	}
+	lines := strings.Split(stanza, "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "bridge-ports" {
+			lines[i] = "    bridge-ports " + value
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func readIfupdownFile(connectedClient *SSHClientContext, file string) (string, error) {
+	return connectedClient.ExecuteCommand(fmt.Sprintf("sudo cat %s 2>/dev/null || true", shellQuote(file)))
+}
+
+// ifupdownMoveToBridge removes name from the bridge-ports of every bridge
+// persisted by the provider, and adds it to bridge if that is given
+func ifupdownMoveToBridge(connectedClient *SSHClientContext, name string, bridge string) error {
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo grep -l -w -e %s %s/linuxhost-* 2>/dev/null || true", shellQuote(name), ifupdownDirectory))
+	if err != nil {
+		return err
+	}
+	files := strings.Fields(result)
+	bridgeFile := ""
+	if bridge != "" {
+		bridgeFile = IfupdownFile(bridge)
+		files = append(files, bridgeFile)
+	}
+	done := map[string]bool{}
+	for _, file := range files {
+		if done[file] {
+			continue
+		}
+		done[file] = true
+		stanza, err := readIfupdownFile(connectedClient, file)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(stanza, "bridge-ports") {
+			if file == bridgeFile {
+				return fmt.Errorf("bridge %s is not persisted with ifupdown, its bridge-ports can't list %s", bridge, name)
+			}
+			continue
+		}
+		ports := []string{}
+		for _, port := range ParseIfupdownPorts(stanza) {
+			if port != name {
+				ports = append(ports, port)
+			}
+		}
+		if file == bridgeFile {
+			ports = append(ports, name)
+		}
+		updated := IfupdownSetPorts(stanza, ports)
+		if updated == stanza {
+			continue
+		}
+		if err := writePersistedFiles(connectedClient, PersistedFiles{file: updated}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *ifupdownPersistence) render(connectedClient *SSHClientContext, iface IsIf) (string, error) {
+	ports := []string{}
+	if _, ok := iface.(*IfBridge); ok {
+		// The ports are maintained by the members
+		current, err := readIfupdownFile(connectedClient, IfupdownFile(iface.GetCommon().Name))
+		if err != nil {
+			return "", err
+		}
+		ports = ParseIfupdownPorts(current)
+	}
+	return RenderIfupdownIf(iface, ports)
+}
+
+func (p *ifupdownPersistence) SaveIf(connectedClient *SSHClientContext, iface IsIf) error {
+	if err := checkPersistable(iface); err != nil {
+		return err
+	}
+	lock := ifupdownLocks.of(connectedClient)
+	lock.Lock()
+	defer lock.Unlock()
+
+	stanza, err := p.render(connectedClient, iface)
+	if err != nil {
+		return err
+	}
+	if err := writePersistedFiles(connectedClient, PersistedFiles{IfupdownFile(iface.GetCommon().Name): stanza}); err != nil {
+		return err
+	}
+	bridge := ""
+	if iface.GetCommon().BridgeMember != nil {
+		bridge = iface.GetCommon().BridgeMember.Name
+	}
+	return ifupdownMoveToBridge(connectedClient, iface.GetCommon().Name, bridge)
+}
+
+func (p *ifupdownPersistence) RemoveIf(connectedClient *SSHClientContext, iface IsIf) error {
+	lock := ifupdownLocks.of(connectedClient)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := removePersistedFiles(connectedClient, PersistedFiles{IfupdownFile(iface.GetCommon().Name): ""}); err != nil {
+		return err
+	}
+	return ifupdownMoveToBridge(connectedClient, iface.GetCommon().Name, "")
+}
+
+func (p *ifupdownPersistence) IfDiff(connectedClient *SSHClientContext, iface IsIf) (string, error) {
+	stanza, err := p.render(connectedClient, iface)
+	if err != nil {
+		return "", err
+	}
+	differs, err := persistedFilesDiff(connectedClient, PersistedFiles{IfupdownFile(iface.GetCommon().Name): stanza})
+	if err != nil || differs != "" {
+		return differs, err
+	}
+	if member := iface.GetCommon().BridgeMember; member != nil {
+		bridge, err := readIfupdownFile(connectedClient, IfupdownFile(member.Name))
+		if err != nil {
+			return "", err
+		}
+		for _, port := range ParseIfupdownPorts(bridge) {
+			if port == iface.GetCommon().Name {
+				return "", nil
+			}
+		}
+		return IfupdownFile(member.Name), nil
+	}
+	return "", nil
+}
+
+func (p *ifupdownPersistence) SaveAddress(connectedClient *SSHClientContext, dev string, address string) error {
+	return writePersistedFiles(connectedClient, PersistedFiles{ifupdownAddressFile(dev, address): RenderIfupdownAddress(dev, address)})
+}
+
+func (p *ifupdownPersistence) RemoveAddress(connectedClient *SSHClientContext, dev string, address string) error {
+	return removePersistedFiles(connectedClient, PersistedFiles{ifupdownAddressFile(dev, address): ""})
+}
+
+func (p *ifupdownPersistence) AddressMatches(connectedClient *SSHClientContext, dev string, address string) (bool, error) {
+	return persistedFilesMatch(connectedClient, PersistedFiles{ifupdownAddressFile(dev, address): RenderIfupdownAddress(dev, address)})
+}
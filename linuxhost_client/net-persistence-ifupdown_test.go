@@ -0,0 +1,87 @@
+package linuxhost_client
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRenderIfupdownIf(t *testing.T) {
+	stp := true
+	forwardDelay := uint32(1500)
+	stanza, err := RenderIfupdownIf(&IfBridge{
+		IfCommon:     IfCommon{Name: "br0", Mtu: 9000},
+		Stp:          &stp,
+		ForwardDelay: &forwardDelay,
+	}, []string{"eth0.10"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Managed by terraform-provider-linuxhost
+auto br0
+iface br0
+    bridge-ports eth0.10
+    bridge-stp on
+    bridge-fd 15
+    bridge-vlan-aware no
+    mtu 9000
+`
+	if stanza != expected {
+		t.Errorf("unexpected stanza:\n%s\nexpected:\n%s", stanza, expected)
+	}
+
+	helloTime := uint32(150)
+	stanza, _ = RenderIfupdownIf(&IfBridge{IfCommon: IfCommon{Name: "br1", Alias: "uplink bridge"}, HelloTime: &helloTime}, nil)
+	if !strings.Contains(stanza, "    bridge-hello 1.5\n") || !strings.Contains(stanza, "    alias uplink bridge\n") {
+		t.Errorf("expected the fraction of the hello time and an unquoted alias:\n%s", stanza)
+	}
+
+	key := uint32(5)
+	if _, err := RenderIfupdownIf(&IfTunnel{IfCommon: IfCommon{Name: "gre1"}, Kind: "gre", Remote: "192.0.2.1", Key: &key}, nil); err == nil {
+		t.Error("expected a tunnel key not to be persisted silently")
+	}
+
+	noPvid := uint32(0)
+	stanza, _ = RenderIfupdownIf(&IfBridge{IfCommon: IfCommon{Name: "br1"}, VlanDefaultPvid: &noPvid}, nil)
+	if !strings.Contains(stanza, "    bridge-pvid 0\n") {
+		t.Errorf("expected a pvid of 0 to be persisted:\n%s", stanza)
+	}
+
+	cost := uint32(10)
+	stanza, err = RenderIfupdownIf(&IfVlan{
+		IfCommon: IfCommon{Name: "eth0.10", State: "down", BridgeMember: &IfBridgeMember{Name: "br0", Cost: &cost}},
+		Vid:      10,
+		Parent:   "eth0",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = `# Managed by terraform-provider-linuxhost
+iface eth0.10
+    vlan-raw-device eth0
+    vlan-id 10
+    bridge-pathcosts 10
+`
+	if stanza != expected {
+		t.Errorf("unexpected stanza:\n%s\nexpected:\n%s", stanza, expected)
+	}
+
+	if IfupdownFile("eth0.10") != "/etc/network/interfaces.d/linuxhost-eth0_10" {
+		t.Errorf("unexpected file name %s", IfupdownFile("eth0.10"))
+	}
+}
+
+func TestIfupdownPorts(t *testing.T) {
+	stanza, _ := RenderIfupdownIf(&IfBridge{IfCommon: IfCommon{Name: "br0"}}, nil)
+	if ports := ParseIfupdownPorts(stanza); len(ports) != 0 {
+		t.Errorf("expected no ports, got %v", ports)
+	}
+	stanza = IfupdownSetPorts(stanza, []string{"dummy0", "veth0"})
+	if ports := ParseIfupdownPorts(stanza); !reflect.DeepEqual(ports, []string{"dummy0", "veth0"}) {
+		t.Errorf("unexpected ports %v", ports)
+	}
+	rendered, _ := RenderIfupdownIf(&IfBridge{IfCommon: IfCommon{Name: "br0"}}, []string{"dummy0", "veth0"})
+	if stanza != rendered {
+		t.Errorf("expected setting the ports to equal rendering them:\n%s\n%s", stanza, rendered)
+	}
+}
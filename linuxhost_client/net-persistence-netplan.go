@@ -0,0 +1,458 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NetplanFile is owned by the provider, every interface and address persisted
+// with netplan is an entry in it
+const NetplanFile = "/etc/netplan/90-terraform-linuxhost.yaml"
+
+// netplanLocks serializes the read-modify-write of NetplanFile per host,
+// resources are applied in parallel
+var netplanLocks hostLocks
+
+// netplanPreservedKeys are set by other resources on an entry: addresses by
+// linuxhost_network_interface_ip and interfaces by bridge members
+var netplanPreservedKeys = []string{"addresses", "interfaces"}
+
+var netplanSections = []string{"ethernets", "bridges", "vlans", "tunnels", "dummy-devices", "virtual-ethernets"}
+
+type netplanPersistence struct {
+	// apply runs `netplan apply` after a successful `netplan generate`
+	apply bool
+}
+
+var _ Persistence = &netplanPersistence{}
+
+func (p *netplanPersistence) SaveIf(connectedClient *SSHClientContext, iface IsIf) error {
+	if err := checkPersistable(iface); err != nil {
+		return err
+	}
+	return p.update(connectedClient, func(network map[string]any) error {
+		return NetplanSetIf(network, iface)
+	})
+}
+
+func (p *netplanPersistence) RemoveIf(connectedClient *SSHClientContext, iface IsIf) error {
+	return p.update(connectedClient, func(network map[string]any) error {
+		NetplanRemoveIf(network, iface.GetCommon().Name)
+		return nil
+	})
+}
+
+func (p *netplanPersistence) IfDiff(connectedClient *SSHClientContext, iface IsIf) (string, error) {
+	network, _, err := readNetplan(connectedClient)
+	if err != nil {
+		return "", err
+	}
+	matches, err := NetplanIfMatches(network, iface)
+	if err != nil || matches {
+		return "", err
+	}
+	return NetplanFile, nil
+}
+
+func (p *netplanPersistence) SaveAddress(connectedClient *SSHClientContext, dev string, address string) error {
+	return p.update(connectedClient, func(network map[string]any) error {
+		NetplanSetAddress(network, dev, address)
+		return nil
+	})
+}
+
+func (p *netplanPersistence) RemoveAddress(connectedClient *SSHClientContext, dev string, address string) error {
+	return p.update(connectedClient, func(network map[string]any) error {
+		NetplanRemoveAddress(network, dev, address)
+		return nil
+	})
+}
+
+func (p *netplanPersistence) AddressMatches(connectedClient *SSHClientContext, dev string, address string) (bool, error) {
+	network, _, err := readNetplan(connectedClient)
+	if err != nil {
+		return false, err
+	}
+	return NetplanAddressMatches(network, dev, address), nil
+}
+
+// readNetplan returns the network mapping of NetplanFile and its raw content,
+// empty if the file doesn't exist
+func readNetplan(connectedClient *SSHClientContext) (map[string]any, string, error) {
+	raw, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo cat %s 2>/dev/null || true", NetplanFile))
+	if err != nil {
+		return nil, "", err
+	}
+	network, err := ParseNetplan(raw)
+	return network, raw, err
+}
+
+// ParseNetplan returns the network mapping of a netplan file
+func ParseNetplan(raw string) (map[string]any, error) {
+	var document map[string]any
+	if err := yaml.Unmarshal([]byte(raw), &document); err != nil {
+		return nil, fmt.Errorf("failed parsing %s: %w", NetplanFile, err)
+	}
+	network, _ := document["network"].(map[string]any)
+	if network == nil {
+		network = map[string]any{}
+	}
+	network["version"] = 2
+	return network, nil
+}
+
+// RenderNetplan returns the content of NetplanFile for the network mapping
+func RenderNetplan(network map[string]any) (string, error) {
+	content, err := yaml.Marshal(map[string]any{"network": network})
+	if err != nil {
+		return "", err
+	}
+	return "# Managed by terraform-provider-linuxhost, manual changes are overwritten\n" + string(content), nil
+}
+
+// update changes NetplanFile and validates it with `netplan generate`. The
+// previous content is restored if that or the optional `netplan apply` fails.
+func (p *netplanPersistence) update(connectedClient *SSHClientContext, change func(network map[string]any) error) error {
+	lock := netplanLocks.of(connectedClient)
+	lock.Lock()
+	defer lock.Unlock()
+
+	network, previous, err := readNetplan(connectedClient)
+	if err != nil {
+		return err
+	}
+	if err := change(network); err != nil {
+		return err
+	}
+	content, err := RenderNetplan(network)
+	if err != nil {
+		return err
+	}
+	if err := writeNetplan(connectedClient, content); err != nil {
+		return err
+	}
+	if _, err := connectedClient.ExecuteCommand("sudo netplan generate"); err != nil {
+		if restoreErr := restoreNetplan(connectedClient, previous, false); restoreErr != nil {
+			return fmt.Errorf("netplan rejected the configuration: %w, restoring %s failed: %w", err, NetplanFile, restoreErr)
+		}
+		return fmt.Errorf("netplan rejected the configuration, %s was restored: %w", NetplanFile, err)
+	}
+	if !p.apply {
+		return nil
+	}
+	if _, err := connectedClient.ExecuteCommand("sudo netplan apply"); err != nil {
+		if restoreErr := restoreNetplan(connectedClient, previous, true); restoreErr != nil {
+			return fmt.Errorf("netplan apply failed: %w, restoring %s failed: %w", err, NetplanFile, restoreErr)
+		}
+		return fmt.Errorf("netplan apply failed, %s was restored and applied again: %w", NetplanFile, err)
+	}
+	return nil
+}
+
+func writeNetplan(connectedClient *SSHClientContext, content string) error {
+	// netplan warns about files readable by others
+	cmd := fmt.Sprintf("sudo mkdir -p /etc/netplan && cat << 'LINUXHOST_EOF' | sudo tee %s > /dev/null && sudo chmod 600 %s\n%sLINUXHOST_EOF", NetplanFile, NetplanFile, content)
+	_, err := connectedClient.ExecuteCommand(cmd)
+	return err
+}
+
+// restoreNetplan writes back the previous content of NetplanFile, removing
+// the file when there was none
+func restoreNetplan(connectedClient *SSHClientContext, previous string, apply bool) error {
+	if previous == "" {
+		if _, err := connectedClient.ExecuteCommand("sudo rm -f " + NetplanFile); err != nil {
+			return err
+		}
+	} else if err := writeNetplan(connectedClient, previous); err != nil {
+		return err
+	}
+	if _, err := connectedClient.ExecuteCommand("sudo netplan generate"); err != nil {
+		return err
+	}
+	if apply {
+		if _, err := connectedClient.ExecuteCommand("sudo netplan apply"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RenderNetplanIf returns the netplan section and entry describing an interface
+func RenderNetplanIf(ifaceX IsIf) (string, map[string]any, error) {
+	iface := ifaceX.GetCommon()
+	entry := map[string]any{}
+	if iface.Mac != "" {
+		entry["macaddress"] = iface.Mac
+	}
+	if iface.Mtu != 0 {
+		entry["mtu"] = iface.Mtu
+	}
+	// An interface that is down stays down at boot
+	if iface.State == "down" {
+		entry["activation-mode"] = "manual"
+	}
+
+	switch m := ifaceX.(type) {
+	case *IfBridge:
+		parameters := map[string]any{}
+		if m.Stp != nil {
+			parameters["stp"] = *m.Stp
+		}
+		// The bridge timers are in centiseconds
+		timers := []struct {
+			key   string
+			value *uint32
+		}{
+			{"forward-delay", m.ForwardDelay},
+			{"hello-time", m.HelloTime},
+			{"max-age", m.MaxAge},
+			{"ageing-time", m.AgeingTime},
+		}
+		for _, timer := range timers {
+			if timer.value != nil {
+				parameters[timer.key] = fmt.Sprintf("%dms", *timer.value*10)
+			}
+		}
+		if m.Priority != nil {
+			parameters["priority"] = *m.Priority
+		}
+		if len(parameters) > 0 {
+			entry["parameters"] = parameters
+		}
+		return "bridges", entry, nil
+	case *IfDummy:
+		return "dummy-devices", entry, nil
+	case *IfVlan:
+		entry["id"] = m.Vid
+		entry["link"] = m.Parent
+		return "vlans", entry, nil
+	case *IfVxlan:
+		entry["mode"] = "vxlan"
+		entry["id"] = m.Vni
+		entry["port"] = m.Port
+		if m.Remote != "" {
+			entry["remote"] = m.Remote
+		}
+		if m.Group != "" {
+			entry["remote"] = m.Group
+		}
+		if m.Local != "" {
+			entry["local"] = m.Local
+		}
+		if m.Dev != "" {
+			entry["link"] = m.Dev
+		}
+		if m.Ttl != 0 {
+			entry["ttl"] = m.Ttl
+		}
+		entry["mac-learning"] = m.Learning
+		if m.SrcPortMin != 0 || m.SrcPortMax != 0 {
+			entry["port-range"] = []any{m.SrcPortMin, m.SrcPortMax}
+		}
+		if m.UdpCsum != nil && *m.UdpCsum {
+			entry["checksums"] = []any{"udp"}
+		}
+		return "tunnels", entry, nil
+	case *IfTunnel:
+		entry["mode"] = m.Kind
+		entry["remote"] = m.Remote
+		if m.Local != "" {
+			entry["local"] = m.Local
+		}
+		if m.Ttl != 0 {
+			entry["ttl"] = m.Ttl
+		}
+		if m.Key != nil {
+			entry["key"] = *m.Key
+		}
+		return "tunnels", entry, nil
+	case *IfVethPeer:
+		entry["peer"] = m.PeerName
+		return "virtual-ethernets", entry, nil
+	}
+	return "", nil, fmt.Errorf("persisting %s with netplan is not supported", iface.Name)
+}
+
+// netplanFind returns the section and entry of name
+func netplanFind(network map[string]any, name string) (string, map[string]any) {
+	for _, section := range netplanSections {
+		entries, _ := network[section].(map[string]any)
+		if entry, ok := entries[name].(map[string]any); ok {
+			return section, entry
+		}
+	}
+	return "", nil
+}
+
+func netplanSet(network map[string]any, section string, name string, entry map[string]any) {
+	entries, _ := network[section].(map[string]any)
+	if entries == nil {
+		entries = map[string]any{}
+		network[section] = entries
+	}
+	entries[name] = entry
+}
+
+// netplanDelete removes the entry of name, empty sections are dropped
+func netplanDelete(network map[string]any, name string) {
+	for _, section := range netplanSections {
+		entries, _ := network[section].(map[string]any)
+		delete(entries, name)
+		if entries != nil && len(entries) == 0 {
+			delete(network, section)
+		}
+	}
+}
+
+func netplanList(entry map[string]any, key string) []any {
+	list, _ := entry[key].([]any)
+	return list
+}
+
+func netplanListContains(list []any, value string) bool {
+	for _, item := range list {
+		if fmt.Sprint(item) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func netplanListRemove(entry map[string]any, key string, value string) {
+	list := []any{}
+	for _, item := range netplanList(entry, key) {
+		if fmt.Sprint(item) != value {
+			list = append(list, item)
+		}
+	}
+	if len(list) == 0 {
+		delete(entry, key)
+	} else {
+		entry[key] = list
+	}
+}
+
+// netplanLeaveBridges removes name from the member list of every bridge,
+// bridges left without any setting are dropped
+func netplanLeaveBridges(network map[string]any, name string) {
+	bridges, _ := network["bridges"].(map[string]any)
+	for bridgeName, bridgeX := range bridges {
+		bridge, ok := bridgeX.(map[string]any)
+		if !ok || !netplanListContains(netplanList(bridge, "interfaces"), name) {
+			continue
+		}
+		netplanListRemove(bridge, "interfaces", name)
+		if len(bridge) == 0 {
+			netplanDelete(network, bridgeName)
+		}
+	}
+}
+
+// NetplanSetIf adds or replaces the entry of an interface, keeping the
+// addresses and bridge members other resources added to it
+func NetplanSetIf(network map[string]any, iface IsIf) error {
+	name := iface.GetCommon().Name
+	section, entry, err := RenderNetplanIf(iface)
+	if err != nil {
+		return err
+	}
+	_, previous := netplanFind(network, name)
+	for _, key := range netplanPreservedKeys {
+		if value, ok := previous[key]; ok {
+			entry[key] = value
+		}
+	}
+	netplanDelete(network, name)
+	netplanSet(network, section, name, entry)
+
+	netplanLeaveBridges(network, name)
+	if member := iface.GetCommon().BridgeMember; member != nil {
+		_, bridge := netplanFind(network, member.Name)
+		if bridge == nil {
+			// The bridge itself isn't persisted, list the member anyway
+			bridge = map[string]any{}
+			netplanSet(network, "bridges", member.Name, bridge)
+		}
+		bridge["interfaces"] = append(netplanList(bridge, "interfaces"), name)
+	}
+	return nil
+}
+
+// NetplanRemoveIf removes the entry of an interface and its bridge membership
+func NetplanRemoveIf(network map[string]any, name string) {
+	netplanDelete(network, name)
+	netplanLeaveBridges(network, name)
+}
+
+// netplanNormalize makes rendered values comparable to parsed ones
+func netplanNormalize(value any) any {
+	content, err := yaml.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var normalized any
+	yaml.Unmarshal(content, &normalized)
+	return normalized
+}
+
+// NetplanIfMatches tells if the entry and bridge membership of an interface
+// are up to date
+func NetplanIfMatches(network map[string]any, iface IsIf) (bool, error) {
+	name := iface.GetCommon().Name
+	section, expected, err := RenderNetplanIf(iface)
+	if err != nil {
+		return false, err
+	}
+	foundSection, found := netplanFind(network, name)
+	if found == nil || foundSection != section {
+		return false, nil
+	}
+	actual := map[string]any{}
+	for key, value := range found {
+		actual[key] = value
+	}
+	for _, key := range netplanPreservedKeys {
+		delete(actual, key)
+	}
+	if !reflect.DeepEqual(netplanNormalize(expected), netplanNormalize(actual)) {
+		return false, nil
+	}
+	if member := iface.GetCommon().BridgeMember; member != nil {
+		_, bridge := netplanFind(network, member.Name)
+		if !netplanListContains(netplanList(bridge, "interfaces"), name) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// NetplanSetAddress adds address to dev. A dev not persisted by the provider
+// gets an ethernets entry holding only its addresses.
+func NetplanSetAddress(network map[string]any, dev string, address string) {
+	_, entry := netplanFind(network, dev)
+	if entry == nil {
+		entry = map[string]any{}
+		netplanSet(network, "ethernets", dev, entry)
+	}
+	if !netplanListContains(netplanList(entry, "addresses"), address) {
+		entry["addresses"] = append(netplanList(entry, "addresses"), address)
+	}
+}
+
+func NetplanRemoveAddress(network map[string]any, dev string, address string) {
+	section, entry := netplanFind(network, dev)
+	if entry == nil {
+		return
+	}
+	netplanListRemove(entry, "addresses", address)
+	if section == "ethernets" && len(entry) == 0 {
+		netplanDelete(network, dev)
+	}
+}
+
+func NetplanAddressMatches(network map[string]any, dev string, address string) bool {
+	_, entry := netplanFind(network, dev)
+	return netplanListContains(netplanList(entry, "addresses"), address)
+}
@@ -0,0 +1,104 @@
+package linuxhost_client
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNetplanSetIf(t *testing.T) {
+	network, err := ParseNetplan("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stp := false
+	bridge := &IfBridge{IfCommon: IfCommon{Name: "br0", Mtu: 9000}, Stp: &stp}
+	member := &IfDummy{IfCommon: IfCommon{Name: "dummy0", BridgeMember: &IfBridgeMember{Name: "br0"}}}
+	vlan := &IfVlan{IfCommon: IfCommon{Name: "br0.10", State: "down"}, Vid: 10, Parent: "br0"}
+	for _, iface := range []IsIf{bridge, member, vlan} {
+		if err := NetplanSetIf(network, iface); err != nil {
+			t.Fatal(err)
+		}
+	}
+	NetplanSetAddress(network, "br0.10", "10.0.10.1/24")
+	NetplanSetAddress(network, "eth0", "192.0.2.10/24")
+
+	content, err := RenderNetplan(network)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `# Managed by terraform-provider-linuxhost, manual changes are overwritten
+network:
+    bridges:
+        br0:
+            interfaces:
+                - dummy0
+            mtu: 9000
+            parameters:
+                stp: false
+    dummy-devices:
+        dummy0: {}
+    ethernets:
+        eth0:
+            addresses:
+                - 192.0.2.10/24
+    version: 2
+    vlans:
+        br0.10:
+            activation-mode: manual
+            addresses:
+                - 10.0.10.1/24
+            id: 10
+            link: br0
+`
+	if content != expected {
+		t.Errorf("unexpected netplan file:\n%s\nexpected:\n%s", content, expected)
+	}
+
+	// Matching works on the parsed file, rewriting keeps the members and addresses
+	parsed, err := ParseNetplan(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iface := range []IsIf{bridge, member, vlan} {
+		if matches, err := NetplanIfMatches(parsed, iface); err != nil || !matches {
+			t.Errorf("expected %s to match, got %v %v", iface.GetCommon().Name, matches, err)
+		}
+	}
+	bridge.Mtu = 1500
+	if matches, _ := NetplanIfMatches(parsed, bridge); matches {
+		t.Error("expected a changed mtu not to match")
+	}
+	if err := NetplanSetIf(parsed, bridge); err != nil {
+		t.Fatal(err)
+	}
+	if !netplanListContains(netplanList(parsed["bridges"].(map[string]any)["br0"].(map[string]any), "interfaces"), "dummy0") {
+		t.Error("expected the bridge to keep its members")
+	}
+	if !NetplanAddressMatches(parsed, "br0.10", "10.0.10.1/24") {
+		t.Error("expected the vlan address to match")
+	}
+
+	NetplanRemoveIf(parsed, "dummy0")
+	NetplanRemoveAddress(parsed, "eth0", "192.0.2.10/24")
+	content, _ = RenderNetplan(parsed)
+	for _, removed := range []string{"dummy", "eth0", "interfaces"} {
+		if strings.Contains(content, removed) {
+			t.Errorf("expected %s to be removed:\n%s", removed, content)
+		}
+	}
+}
+
+func TestNetplanBridgeStub(t *testing.T) {
+	network, _ := ParseNetplan("")
+	member := &IfVethPeer{IfCommon: IfCommon{Name: "veth0", BridgeMember: &IfBridgeMember{Name: "br9"}}, PeerName: "veth1"}
+	if err := NetplanSetIf(network, member); err != nil {
+		t.Fatal(err)
+	}
+	if matches, _ := NetplanIfMatches(network, member); !matches {
+		t.Error("expected veth0 to match")
+	}
+	NetplanRemoveIf(network, "veth0")
+	if _, ok := network["bridges"]; ok {
+		t.Errorf("expected the bridge stub to be removed, got %v", network)
+	}
+}
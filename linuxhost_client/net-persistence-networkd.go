@@ -0,0 +1,266 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const networkdDirectory = "/etc/systemd/network"
+
+var networkdPersistence = &filePersistence{
+	renderIf: func(connectedClient *SSHClientContext, iface IsIf) (PersistedFiles, error) {
+		return RenderNetworkdIf(iface, func(dev string) (string, error) {
+			return networkdNetworkFile(connectedClient, dev)
+		})
+	},
+	renderAddress: func(connectedClient *SSHClientContext, dev string, address string) (PersistedFiles, error) {
+		return RenderNetworkdAddress(dev, address, func(dev string) (string, error) {
+			return networkdNetworkFile(connectedClient, dev)
+		})
+	},
+}
+
+// networkdBase is the path of the files owned by the provider, without the suffix
+func networkdBase(name string) string {
+	return fmt.Sprintf("%s/50-linuxhost-%s", networkdDirectory, name)
+}
+
+// networkdDropIn is a drop-in extending the .network file of another interface
+func networkdDropIn(networkFile string, name string) string {
+	return fmt.Sprintf("%s/%s.d/50-linuxhost-%s.conf", networkdDirectory, path.Base(networkFile), name)
+}
+
+// networkdNetworkFile finds the .network file configuring dev, either the one
+// written by the provider or the one networkd reports for the link
+func networkdNetworkFile(connectedClient *SSHClientContext, dev string) (string, error) {
+	own := networkdBase(dev) + ".network"
+	cmd := fmt.Sprintf("if sudo test -f %s; then echo %s; else networkctl status --no-pager -- %s 2>/dev/null | sed -n 's/^ *Network File: //p'; fi", shellQuote(own), shellQuote(own), shellQuote(dev))
+	result, err := connectedClient.ExecuteCommand(cmd)
+	if err != nil {
+		return "", err
+	}
+	file := strings.TrimSpace(result)
+	if file == "" || file == "n/a" {
+		return "", fmt.Errorf("no systemd-networkd .network file configures %s, persist it first", dev)
+	}
+	return file, nil
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
+// RenderNetworkdIf renders the .netdev and .network files of an interface.
+// networkFile looks up the .network file of the parent a VLAN or VXLAN is
+// attached to, the attachment is added as a drop-in to it.
+func RenderNetworkdIf(ifaceX IsIf, networkFile func(dev string) (string, error)) (PersistedFiles, error) {
+	iface := ifaceX.GetCommon()
+	files := PersistedFiles{}
+	netdev := newIniFile()
+	netdev.Set("NetDev", "Name", iface.Name)
+
+	parent := ""
+	attach := ""
+	switch m := ifaceX.(type) {
+	case *IfBridge:
+		netdev.Set("NetDev", "Kind", "bridge")
+		if m.Stp != nil {
+			netdev.Set("Bridge", "STP", yesNo(*m.Stp))
+		}
+		// The bridge timers are in centiseconds
+		if m.ForwardDelay != nil {
+			netdev.Set("Bridge", "ForwardDelaySec", fmt.Sprintf("%dms", *m.ForwardDelay*10))
+		}
+		if m.HelloTime != nil {
+			netdev.Set("Bridge", "HelloTimeSec", fmt.Sprintf("%dms", *m.HelloTime*10))
+		}
+		if m.MaxAge != nil {
+			netdev.Set("Bridge", "MaxAgeSec", fmt.Sprintf("%dms", *m.MaxAge*10))
+		}
+		if m.AgeingTime != nil {
+			netdev.Set("Bridge", "AgeingTimeSec", fmt.Sprintf("%dms", *m.AgeingTime*10))
+		}
+		if m.Priority != nil {
+			netdev.Set("Bridge", "Priority", fmt.Sprint(*m.Priority))
+		}
+		if m.GroupFwdMask != nil {
+			netdev.Set("Bridge", "GroupForwardMask", fmt.Sprint(*m.GroupFwdMask))
+		}
+		if m.McastSnooping != nil {
+			netdev.Set("Bridge", "MulticastSnooping", yesNo(*m.McastSnooping))
+		}
+		if m.McastQuerier != nil {
+			netdev.Set("Bridge", "MulticastQuerier", yesNo(*m.McastQuerier))
+		}
+		if m.McastIgmpVersion != nil {
+			netdev.Set("Bridge", "MulticastIGMPVersion", fmt.Sprint(*m.McastIgmpVersion))
+		}
+		netdev.Set("Bridge", "VLANFiltering", yesNo(m.VlanFiltering))
+		if m.VlanProtocol != "" {
+			netdev.Set("Bridge", "VLANProtocol", m.VlanProtocol)
+		}
+		if m.VlanDefaultPvid != nil && *m.VlanDefaultPvid == 0 {
+			netdev.Set("Bridge", "DefaultPVID", "none")
+		} else if m.VlanDefaultPvid != nil {
+			netdev.Set("Bridge", "DefaultPVID", fmt.Sprint(*m.VlanDefaultPvid))
+		}
+	case *IfDummy:
+		netdev.Set("NetDev", "Kind", "dummy")
+	case *IfVlan:
+		netdev.Set("NetDev", "Kind", "vlan")
+		netdev.Set("VLAN", "Id", fmt.Sprint(m.Vid))
+		parent = m.Parent
+		attach = "VLAN"
+	case *IfVxlan:
+		netdev.Set("NetDev", "Kind", "vxlan")
+		netdev.Set("VXLAN", "VNI", fmt.Sprint(m.Vni))
+		if m.Remote != "" {
+			netdev.Set("VXLAN", "Remote", m.Remote)
+		}
+		if m.Local != "" {
+			netdev.Set("VXLAN", "Local", m.Local)
+		}
+		if m.Group != "" {
+			netdev.Set("VXLAN", "Group", m.Group)
+		}
+		if m.Ttl != 0 {
+			netdev.Set("VXLAN", "TTL", fmt.Sprint(m.Ttl))
+		}
+		// A tos of 1 means inherit, which networkd can't express
+		if m.Tos > 1 {
+			netdev.Set("VXLAN", "TOS", fmt.Sprint(m.Tos))
+		}
+		netdev.Set("VXLAN", "MacLearning", yesNo(m.Learning))
+		netdev.Set("VXLAN", "DestinationPort", fmt.Sprint(m.Port))
+		if m.SrcPortMin != 0 || m.SrcPortMax != 0 {
+			netdev.Set("VXLAN", "PortRange", fmt.Sprintf("%d-%d", m.SrcPortMin, m.SrcPortMax))
+		}
+		if m.UdpCsum != nil {
+			netdev.Set("VXLAN", "UDPChecksum", yesNo(*m.UdpCsum))
+		}
+		if m.External {
+			netdev.Set("VXLAN", "External", "yes")
+		}
+		if m.Dev == "" {
+			netdev.Set("VXLAN", "Independent", "yes")
+		} else {
+			parent = m.Dev
+			attach = "VXLAN"
+		}
+	case *IfTunnel:
+		netdev.Set("NetDev", "Kind", m.Kind)
+		if m.Local != "" {
+			netdev.Set("Tunnel", "Local", m.Local)
+		}
+		netdev.Set("Tunnel", "Remote", m.Remote)
+		if m.Ttl != 0 {
+			netdev.Set("Tunnel", "TTL", fmt.Sprint(m.Ttl))
+		}
+		if m.Key != nil {
+			netdev.Set("Tunnel", "Key", fmt.Sprint(*m.Key))
+		}
+		netdev.Set("Tunnel", "DiscoverPathMTU", yesNo(m.PmtuDisc))
+		netdev.Set("Tunnel", "Independent", "yes")
+	case *IfVethPeer:
+		// The .netdev of a pair is written by the local end only, it
+		// creates both ends
+		if !m.Local {
+			netdev = nil
+		} else {
+			netdev.Set("NetDev", "Kind", "veth")
+			netdev.Set("Peer", "Name", m.PeerName)
+		}
+	default:
+		return nil, fmt.Errorf("persisting %s with systemd-networkd is not supported", iface.Name)
+	}
+	if netdev != nil {
+		files[networkdBase(iface.Name)+".netdev"] = netdev.String()
+	}
+
+	network, err := renderNetworkdNetwork(iface)
+	if err != nil {
+		return nil, err
+	}
+	files[networkdBase(iface.Name)+".network"] = network.String()
+
+	if parent != "" {
+		parentFile, err := networkFile(parent)
+		if err != nil {
+			return nil, err
+		}
+		dropIn := newIniFile()
+		dropIn.Set("Network", attach, iface.Name)
+		files[networkdDropIn(parentFile, iface.Name)] = dropIn.String()
+	}
+	return files, nil
+}
+
+func renderNetworkdNetwork(iface *IfCommon) (*iniFile, error) {
+	network := newIniFile()
+	network.Set("Match", "Name", iface.Name)
+	if iface.Mac != "" {
+		network.Set("Link", "MACAddress", iface.Mac)
+	}
+	if iface.Mtu != 0 {
+		network.Set("Link", "MTUBytes", fmt.Sprint(iface.Mtu))
+	}
+	if iface.Group != "" && iface.Group != "default" {
+		if _, err := strconv.ParseUint(iface.Group, 10, 32); err != nil {
+			return nil, fmt.Errorf("interface %s: systemd-networkd only supports numeric groups, got %q", iface.Name, iface.Group)
+		}
+		network.Set("Link", "Group", iface.Group)
+	}
+	if iface.State != "" {
+		network.Set("Link", "ActivationPolicy", iface.State)
+	}
+	// Keep the link configured even without carrier, e.g. an empty bridge
+	network.Set("Network", "ConfigureWithoutCarrier", "yes")
+	if member := iface.BridgeMember; member != nil {
+		network.Set("Network", "Bridge", member.Name)
+		if member.Cost != nil {
+			network.Set("Bridge", "Cost", fmt.Sprint(*member.Cost))
+		}
+		if member.Priority != nil {
+			network.Set("Bridge", "Priority", fmt.Sprint(*member.Priority))
+		}
+		bridgePortFlags := []struct {
+			key   string
+			value *bool
+		}{
+			{"HairPin", member.Hairpin},
+			{"Learning", member.Learning},
+			{"UnicastFlood", member.Flood},
+			{"MulticastFlood", member.McastFlood},
+			{"NeighborSuppression", member.NeighSuppress},
+			{"Isolated", member.Isolated},
+		}
+		for _, flag := range bridgePortFlags {
+			if flag.value != nil {
+				network.Set("Bridge", flag.key, yesNo(*flag.value))
+			}
+		}
+		// BPDU guard blocks BPDUs, networkd calls it the inverse
+		if member.BpduGuard != nil {
+			network.Set("Bridge", "UseBPDU", yesNo(!*member.BpduGuard))
+		}
+	}
+	return network, nil
+}
+
+// RenderNetworkdAddress renders the drop-in adding address to the .network
+// file of dev
+func RenderNetworkdAddress(dev string, address string, networkFile func(dev string) (string, error)) (PersistedFiles, error) {
+	file, err := networkFile(dev)
+	if err != nil {
+		return nil, err
+	}
+	dropIn := newIniFile()
+	dropIn.Set("Network", "Address", address)
+	name := "address-" + strings.NewReplacer("/", "_", ":", "-").Replace(address)
+	return PersistedFiles{networkdDropIn(file, name): dropIn.String()}, nil
+}
@@ -0,0 +1,115 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"testing"
+)
+
+func networkFileOf(dev string) (string, error) {
+	if dev == "eth0" {
+		return "/etc/systemd/network/10-eth0.network", nil
+	}
+	return "", fmt.Errorf("no network file for %s", dev)
+}
+
+func TestRenderNetworkdBridgeMember(t *testing.T) {
+	stp := true
+	forwardDelay := uint32(1500)
+	cost := uint32(100)
+	guard := true
+	files, err := RenderNetworkdIf(&IfBridge{
+		IfCommon:     IfCommon{Name: "br0", State: "up", Mtu: 9000},
+		Stp:          &stp,
+		ForwardDelay: &forwardDelay,
+	}, networkFileOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNetdev := "[NetDev]\nName=br0\nKind=bridge\n\n[Bridge]\nSTP=yes\nForwardDelaySec=15000ms\nVLANFiltering=no\n"
+	if got := files["/etc/systemd/network/50-linuxhost-br0.netdev"]; got != expectNetdev {
+		t.Errorf("bridge netdev:\n%s\nexpected:\n%s", got, expectNetdev)
+	}
+	expectNetwork := "[Match]\nName=br0\n\n[Link]\nMTUBytes=9000\nActivationPolicy=up\n\n[Network]\nConfigureWithoutCarrier=yes\n"
+	if got := files["/etc/systemd/network/50-linuxhost-br0.network"]; got != expectNetwork {
+		t.Errorf("bridge network:\n%s\nexpected:\n%s", got, expectNetwork)
+	}
+
+	noPvid := uint32(0)
+	files, err = RenderNetworkdIf(&IfBridge{IfCommon: IfCommon{Name: "br1"}, VlanDefaultPvid: &noPvid}, networkFileOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNetdev = "[NetDev]\nName=br1\nKind=bridge\n\n[Bridge]\nVLANFiltering=no\nDefaultPVID=none\n"
+	if got := files["/etc/systemd/network/50-linuxhost-br1.netdev"]; got != expectNetdev {
+		t.Errorf("bridge netdev without pvid:\n%s\nexpected:\n%s", got, expectNetdev)
+	}
+
+	files, err = RenderNetworkdIf(&IfDummy{
+		IfCommon: IfCommon{Name: "dummy0", Mac: "52:54:00:12:34:56", BridgeMember: &IfBridgeMember{
+			Name: "br0", Cost: &cost, BpduGuard: &guard,
+		}},
+	}, networkFileOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNetwork = "[Match]\nName=dummy0\n\n[Link]\nMACAddress=52:54:00:12:34:56\n\n[Network]\nConfigureWithoutCarrier=yes\nBridge=br0\n\n[Bridge]\nCost=100\nUseBPDU=no\n"
+	if got := files["/etc/systemd/network/50-linuxhost-dummy0.network"]; got != expectNetwork {
+		t.Errorf("member network:\n%s\nexpected:\n%s", got, expectNetwork)
+	}
+}
+
+func TestRenderNetworkdAttached(t *testing.T) {
+	files, err := RenderNetworkdIf(&IfVlan{IfCommon: IfCommon{Name: "eth0.10"}, Vid: 10, Parent: "eth0"}, networkFileOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 3 {
+		t.Errorf("expected netdev, network and drop-in, got %v", files)
+	}
+	if got := files["/etc/systemd/network/50-linuxhost-eth0.10.netdev"]; got != "[NetDev]\nName=eth0.10\nKind=vlan\n\n[VLAN]\nId=10\n" {
+		t.Errorf("unexpected vlan netdev:\n%s", got)
+	}
+	if got := files["/etc/systemd/network/10-eth0.network.d/50-linuxhost-eth0.10.conf"]; got != "[Network]\nVLAN=eth0.10\n" {
+		t.Errorf("unexpected vlan drop-in:\n%s", got)
+	}
+
+	if _, err := RenderNetworkdIf(&IfVlan{IfCommon: IfCommon{Name: "eth1.10"}, Vid: 10, Parent: "eth1"}, networkFileOf); err == nil {
+		t.Error("expected an error for a parent without network file")
+	}
+
+	files, err = RenderNetworkdIf(&IfVxlan{IfCommon: IfCommon{Name: "vx0"}, Vni: 42, Port: 4789, Remote: "192.0.2.1"}, networkFileOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectNetdev := "[NetDev]\nName=vx0\nKind=vxlan\n\n[VXLAN]\nVNI=42\nRemote=192.0.2.1\nMacLearning=no\nDestinationPort=4789\nIndependent=yes\n"
+	if got := files["/etc/systemd/network/50-linuxhost-vx0.netdev"]; got != expectNetdev {
+		t.Errorf("vxlan netdev:\n%s\nexpected:\n%s", got, expectNetdev)
+	}
+}
+
+func TestRenderNetworkdVeth(t *testing.T) {
+	local, err := RenderNetworkdIf(&IfVethPeer{IfCommon: IfCommon{Name: "veth0"}, PeerName: "veth1", Local: true}, networkFileOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := local["/etc/systemd/network/50-linuxhost-veth0.netdev"]; got != "[NetDev]\nName=veth0\nKind=veth\n\n[Peer]\nName=veth1\n" {
+		t.Errorf("unexpected veth netdev:\n%s", got)
+	}
+	peer, err := RenderNetworkdIf(&IfVethPeer{IfCommon: IfCommon{Name: "veth1"}, PeerName: "veth0"}, networkFileOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := peer["/etc/systemd/network/50-linuxhost-veth1.netdev"]; ok || len(peer) != 1 {
+		t.Errorf("expected only a .network for the peer end, got %v", peer)
+	}
+}
+
+func TestRenderNetworkdAddress(t *testing.T) {
+	files, err := RenderNetworkdAddress("eth0", "10.0.0.1/24", networkFileOf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := files["/etc/systemd/network/10-eth0.network.d/50-linuxhost-address-10.0.0.1_24.conf"]; got != "[Network]\nAddress=10.0.0.1/24\n" {
+		t.Errorf("unexpected address drop-in: %v", files)
+	}
+}
@@ -0,0 +1,411 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// NmcliSetting is a property of a NetworkManager connection profile
+type NmcliSetting struct {
+	Key   string
+	Value string
+}
+
+// NmcliConnectionName is the profile owned by the provider for an interface
+func NmcliConnectionName(name string) string {
+	return "linuxhost-" + name
+}
+
+type nmcliPersistence struct{}
+
+var _ Persistence = &nmcliPersistence{}
+
+// RenderNmcliIf returns the connection type and the settings of the profile
+// describing an interface
+func RenderNmcliIf(ifaceX IsIf) (string, []NmcliSetting, error) {
+	iface := ifaceX.GetCommon()
+	settings := []NmcliSetting{}
+	set := func(key string, value string) {
+		settings = append(settings, NmcliSetting{key, value})
+	}
+	connectionType := ""
+	switch m := ifaceX.(type) {
+	case *IfBridge:
+		connectionType = "bridge"
+		if iface.Mac != "" {
+			set("bridge.mac-address", iface.Mac)
+		}
+		if m.Stp != nil {
+			set("bridge.stp", yesNo(*m.Stp))
+		}
+		// NetworkManager has the timers in whole seconds, the bridge in centiseconds
+		timers := []struct {
+			key   string
+			value *uint32
+		}{
+			{"bridge.forward-delay", m.ForwardDelay},
+			{"bridge.hello-time", m.HelloTime},
+			{"bridge.max-age", m.MaxAge},
+			{"bridge.ageing-time", m.AgeingTime},
+		}
+		for _, timer := range timers {
+			if timer.value == nil {
+				continue
+			}
+			if *timer.value%100 != 0 {
+				return "", nil, fmt.Errorf("%s of %s is %s seconds, NetworkManager only holds whole seconds", timer.key, iface.Name, seconds(*timer.value))
+			}
+			set(timer.key, fmt.Sprint(*timer.value/100))
+		}
+		if m.Priority != nil {
+			set("bridge.priority", fmt.Sprint(*m.Priority))
+		}
+		if m.GroupFwdMask != nil {
+			set("bridge.group-forward-mask", fmt.Sprint(*m.GroupFwdMask))
+		}
+		if m.McastSnooping != nil {
+			set("bridge.multicast-snooping", yesNo(*m.McastSnooping))
+		}
+		if m.McastQuerier != nil {
+			set("bridge.multicast-querier", yesNo(*m.McastQuerier))
+		}
+		set("bridge.vlan-filtering", yesNo(m.VlanFiltering))
+		if m.VlanDefaultPvid != nil {
+			set("bridge.vlan-default-pvid", fmt.Sprint(*m.VlanDefaultPvid))
+		}
+		if m.VlanProtocol != "" {
+			set("bridge.vlan-protocol", m.VlanProtocol)
+		}
+	case *IfDummy:
+		connectionType = "dummy"
+	case *IfVlan:
+		connectionType = "vlan"
+		set("vlan.parent", m.Parent)
+		set("vlan.id", fmt.Sprint(m.Vid))
+	case *IfVxlan:
+		connectionType = "vxlan"
+		set("vxlan.id", fmt.Sprint(m.Vni))
+		set("vxlan.destination-port", fmt.Sprint(m.Port))
+		if m.Remote != "" {
+			set("vxlan.remote", m.Remote)
+		}
+		if m.Group != "" {
+			set("vxlan.remote", m.Group)
+		}
+		if m.Local != "" {
+			set("vxlan.local", m.Local)
+		}
+		if m.Dev != "" {
+			set("vxlan.parent", m.Dev)
+		}
+		if m.Ttl != 0 {
+			set("vxlan.ttl", fmt.Sprint(m.Ttl))
+		}
+		// A tos of 1 means inherit, which NetworkManager can't express
+		if m.Tos > 1 {
+			set("vxlan.tos", fmt.Sprint(m.Tos))
+		}
+		set("vxlan.learning", yesNo(m.Learning))
+		if m.SrcPortMin != 0 || m.SrcPortMax != 0 {
+			set("vxlan.source-port-min", fmt.Sprint(m.SrcPortMin))
+			set("vxlan.source-port-max", fmt.Sprint(m.SrcPortMax))
+		}
+	case *IfTunnel:
+		connectionType = "ip-tunnel"
+		set("ip-tunnel.mode", m.Kind)
+		set("ip-tunnel.remote", m.Remote)
+		if m.Local != "" {
+			set("ip-tunnel.local", m.Local)
+		}
+		set("ip-tunnel.ttl", fmt.Sprint(m.Ttl))
+		if m.Key != nil {
+			set("ip-tunnel.input-key", fmt.Sprint(*m.Key))
+			set("ip-tunnel.output-key", fmt.Sprint(*m.Key))
+		}
+		set("ip-tunnel.path-mtu-discovery", yesNo(m.PmtuDisc))
+		if iface.Mtu != 0 {
+			set("ip-tunnel.mtu", fmt.Sprint(iface.Mtu))
+		}
+	case *IfVethPeer:
+		connectionType = "veth"
+		set("veth.peer", m.PeerName)
+	default:
+		return "", nil, fmt.Errorf("managing %s with NetworkManager is not supported", iface.Name)
+	}
+
+	if connectionType != "bridge" && connectionType != "ip-tunnel" && iface.Mac != "" {
+		set("ethernet.cloned-mac-address", iface.Mac)
+	}
+	if connectionType != "ip-tunnel" && iface.Mtu != 0 {
+		set("ethernet.mtu", fmt.Sprint(iface.Mtu))
+	}
+	// NetworkManager activates a virtual device on its own unless told not to
+	set("connection.autoconnect", yesNo(iface.State != "down"))
+	if iface.BridgeMember == nil {
+		set("connection.master", "")
+		set("connection.slave-type", "")
+	}
+	if member := iface.BridgeMember; member != nil {
+		set("connection.master", member.Name)
+		set("connection.slave-type", "bridge")
+		if member.Cost != nil {
+			set("bridge-port.path-cost", fmt.Sprint(*member.Cost))
+		}
+		if member.Priority != nil {
+			set("bridge-port.priority", fmt.Sprint(*member.Priority))
+		}
+		if member.Hairpin != nil {
+			set("bridge-port.hairpin-mode", yesNo(*member.Hairpin))
+		}
+	}
+	return connectionType, settings, nil
+}
+
+func nmcliArguments(settings []NmcliSetting) string {
+	arguments := ""
+	for _, setting := range settings {
+		arguments = arguments + " " + setting.Key + " " + shellQuote(setting.Value)
+	}
+	return arguments
+}
+
+// ParseNmcliFields parses the `nmcli -t -f` output of a single profile, in
+// terse mode colons within values are escaped with a backslash
+func ParseNmcliFields(output string) map[string]string {
+	fields := map[string]string{}
+	for _, line := range strings.Split(output, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.NewReplacer(`\:`, ":", `\\`, `\`).Replace(value)
+		fields[key] = value
+	}
+	return fields
+}
+
+// NmcliSettingsMatch compares rendered settings with the profile. nmcli
+// prints mac addresses in upper case.
+func NmcliSettingsMatch(settings []NmcliSetting, fields map[string]string) bool {
+	for _, setting := range settings {
+		value, ok := fields[setting.Key]
+		if !ok || !strings.EqualFold(value, setting.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// NmcliSupports tells if the interface can be a NetworkManager profile
+func NmcliSupports(iface IsIf) bool {
+	switch iface.(type) {
+	case *IfBridge, *IfDummy, *IfVlan, *IfVxlan, *IfTunnel, *IfVethPeer:
+		return true
+	}
+	return false
+}
+
+// NmcliConnectionExists tells if a profile with the name exists
+func NmcliConnectionExists(connectedClient *SSHClientContext, connection string) (bool, error) {
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo nmcli -g connection.id connection show id %s 2>/dev/null || true", shellQuote(connection)))
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(result) != "", nil
+}
+
+// NmcliCreateIf adds the profile of an interface and activates it, unless
+// its state is down. NetworkManager creates the device, the settings the
+// profile doesn't hold like the alias and bridge port flags are set with ip.
+func NmcliCreateIf(connectedClient *SSHClientContext, iface IsIf) error {
+	if err := checkPersistable(iface); err != nil {
+		return err
+	}
+	if err := nmcliAdd(connectedClient, iface); err != nil {
+		return err
+	}
+	if iface.GetCommon().State == "down" {
+		return nil
+	}
+	if _, err := connectedClient.ExecuteCommand("sudo nmcli connection up id " + shellQuote(NmcliConnectionName(iface.GetCommon().Name))); err != nil {
+		return err
+	}
+	if err := IfSetCommon(connectedClient, iface); err != nil {
+		return *err
+	}
+	return nil
+}
+
+func nmcliAdd(connectedClient *SSHClientContext, iface IsIf) error {
+	connectionType, settings, err := RenderNmcliIf(iface)
+	if err != nil {
+		return err
+	}
+	name := iface.GetCommon().Name
+	cmd := fmt.Sprintf("sudo nmcli connection add type %s con-name %s ifname %s", connectionType, shellQuote(NmcliConnectionName(name)), shellQuote(name))
+	if iface.GetCommon().BridgeMember == nil {
+		// Addresses are added by linuxhost_network_interface_ip
+		cmd = cmd + " ipv4.method disabled ipv6.method ignore"
+	}
+	_, err = connectedClient.ExecuteCommand(cmd + nmcliArguments(settings))
+	return err
+}
+
+// SaveIf adds the profile or modifies it without activating the changes,
+// the live state is set with ip
+func (p *nmcliPersistence) SaveIf(connectedClient *SSHClientContext, iface IsIf) error {
+	if err := checkPersistable(iface); err != nil {
+		return err
+	}
+	connection := NmcliConnectionName(iface.GetCommon().Name)
+	exists, err := NmcliConnectionExists(connectedClient, connection)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return nmcliAdd(connectedClient, iface)
+	}
+	_, settings, err := RenderNmcliIf(iface)
+	if err != nil {
+		return err
+	}
+	_, err = connectedClient.ExecuteCommand("sudo nmcli connection modify id " + shellQuote(connection) + nmcliArguments(settings))
+	return err
+}
+
+func (p *nmcliPersistence) RemoveIf(connectedClient *SSHClientContext, iface IsIf) error {
+	connection := NmcliConnectionName(iface.GetCommon().Name)
+	exists, err := NmcliConnectionExists(connectedClient, connection)
+	if err != nil || !exists {
+		return err
+	}
+	_, err = connectedClient.ExecuteCommand("sudo nmcli connection delete id " + shellQuote(connection))
+	return err
+}
+
+func (p *nmcliPersistence) IfDiff(connectedClient *SSHClientContext, iface IsIf) (string, error) {
+	connection := NmcliConnectionName(iface.GetCommon().Name)
+	profile := "NetworkManager profile " + connection
+	exists, err := NmcliConnectionExists(connectedClient, connection)
+	if err != nil {
+		return "", err
+	}
+	if !exists {
+		return profile, nil
+	}
+	_, settings, err := RenderNmcliIf(iface)
+	if err != nil {
+		return "", err
+	}
+	keys := []string{}
+	for _, setting := range settings {
+		keys = append(keys, setting.Key)
+	}
+	if len(keys) == 0 {
+		return "", nil
+	}
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo nmcli -t -f %s connection show id %s", strings.Join(keys, ","), shellQuote(connection)))
+	if err != nil {
+		return "", err
+	}
+	if !NmcliSettingsMatch(settings, ParseNmcliFields(result)) {
+		return profile, nil
+	}
+	return "", nil
+}
+
+// nmcliDevConnection returns the profile configuring dev, the one owned by
+// the provider or the one active on the device
+func nmcliDevConnection(connectedClient *SSHClientContext, dev string) (string, error) {
+	own := NmcliConnectionName(dev)
+	exists, err := NmcliConnectionExists(connectedClient, own)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return own, nil
+	}
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo nmcli -g GENERAL.CONNECTION device show %s 2>/dev/null || true", shellQuote(dev)))
+	if err != nil {
+		return "", err
+	}
+	connection := strings.TrimSpace(result)
+	if connection == "" || connection == "--" {
+		return "", fmt.Errorf("no NetworkManager profile configures %s", dev)
+	}
+	return connection, nil
+}
+
+// nmcliAddressFamily is the setting holding address, ipv4 or ipv6
+func nmcliAddressFamily(address string) string {
+	ip, _, err := net.ParseCIDR(address)
+	if err == nil && ip.To4() == nil {
+		return "ipv6"
+	}
+	return "ipv4"
+}
+
+func nmcliAddresses(connectedClient *SSHClientContext, connection string, family string) ([]string, error) {
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo nmcli -g %s.addresses connection show id %s", family, shellQuote(connection)))
+	if err != nil {
+		return nil, err
+	}
+	addresses := []string{}
+	for _, address := range strings.Split(strings.TrimSpace(result), ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
+
+func (p *nmcliPersistence) SaveAddress(connectedClient *SSHClientContext, dev string, address string) error {
+	connection, err := nmcliDevConnection(connectedClient, dev)
+	if err != nil {
+		return err
+	}
+	family := nmcliAddressFamily(address)
+	_, err = connectedClient.ExecuteCommand(fmt.Sprintf("sudo nmcli connection modify id %s %s.method manual +%s.addresses %s", shellQuote(connection), family, family, shellQuote(address)))
+	return err
+}
+
+func (p *nmcliPersistence) RemoveAddress(connectedClient *SSHClientContext, dev string, address string) error {
+	connection, err := nmcliDevConnection(connectedClient, dev)
+	if err != nil {
+		return err
+	}
+	family := nmcliAddressFamily(address)
+	addresses, err := nmcliAddresses(connectedClient, connection, family)
+	if err != nil {
+		return err
+	}
+	cmd := fmt.Sprintf("sudo nmcli connection modify id %s -%s.addresses %s", shellQuote(connection), family, shellQuote(address))
+	// A manual method needs an address
+	if len(addresses) == 1 && addresses[0] == address {
+		if family == "ipv6" {
+			cmd = cmd + " ipv6.method ignore"
+		} else {
+			cmd = cmd + " ipv4.method disabled"
+		}
+	}
+	_, err = connectedClient.ExecuteCommand(cmd)
+	return err
+}
+
+func (p *nmcliPersistence) AddressMatches(connectedClient *SSHClientContext, dev string, address string) (bool, error) {
+	connection, err := nmcliDevConnection(connectedClient, dev)
+	if err != nil {
+		return false, err
+	}
+	addresses, err := nmcliAddresses(connectedClient, connection, nmcliAddressFamily(address))
+	if err != nil {
+		return false, err
+	}
+	for _, a := range addresses {
+		if a == address {
+			return true, nil
+		}
+	}
+	return false, nil
+}
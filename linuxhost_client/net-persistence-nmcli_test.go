@@ -0,0 +1,90 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+func TestRenderNmcliIf(t *testing.T) {
+	forwardDelay := uint32(1500)
+	connectionType, settings, err := RenderNmcliIf(&IfBridge{
+		IfCommon:     IfCommon{Name: "br0", Mac: "52:54:00:12:34:56"},
+		ForwardDelay: &forwardDelay,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if connectionType != "bridge" {
+		t.Errorf("expected a bridge connection, got %s", connectionType)
+	}
+	expected := " bridge.mac-address '52:54:00:12:34:56' bridge.forward-delay '15' bridge.vlan-filtering 'no' connection.autoconnect 'yes' connection.master '' connection.slave-type ''"
+	if got := nmcliArguments(settings); got != expected {
+		t.Errorf("unexpected arguments:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	helloTime := uint32(150)
+	bridge := &IfBridge{IfCommon: IfCommon{Name: "br1"}, HelloTime: &helloTime}
+	if _, _, err := RenderNmcliIf(bridge); err == nil {
+		t.Error("expected a hello time of 1.5 seconds not to be truncated")
+	}
+	if !NmcliSupports(bridge) {
+		t.Error("expected the bridge to stay managed by NetworkManager")
+	}
+
+	noPvid := uint32(0)
+	_, settings, err = RenderNmcliIf(&IfBridge{IfCommon: IfCommon{Name: "br1"}, VlanDefaultPvid: &noPvid})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = " bridge.vlan-filtering 'no' bridge.vlan-default-pvid '0' connection.autoconnect 'yes' connection.master '' connection.slave-type ''"
+	if got := nmcliArguments(settings); got != expected {
+		t.Errorf("unexpected arguments:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	_, settings, err = RenderNmcliIf(&IfVlan{
+		IfCommon: IfCommon{Name: "eth0.10", BridgeMember: &IfBridgeMember{Name: "br0"}},
+		Vid:      10,
+		Parent:   "eth0",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = " vlan.parent 'eth0' vlan.id '10' connection.autoconnect 'yes' connection.master 'br0' connection.slave-type 'bridge'"
+	if got := nmcliArguments(settings); got != expected {
+		t.Errorf("unexpected arguments:\n%s\nexpected:\n%s", got, expected)
+	}
+
+	if NmcliSupports(&IfWireguard{IfCommon: IfCommon{Name: "wg0"}}) {
+		t.Error("expected WireGuard not to be supported")
+	}
+}
+
+func TestNmcliSettingsMatch(t *testing.T) {
+	output := `bridge.mac-address:52\:54\:00\:12\:34\:56
+bridge.forward-delay:15
+bridge.vlan-filtering:no
+connection.autoconnect:yes
+connection.master:
+connection.slave-type:
+`
+	fields := ParseNmcliFields(output)
+	if fields["bridge.mac-address"] != "52:54:00:12:34:56" {
+		t.Errorf("expected the escaped colons to be unescaped, got %q", fields["bridge.mac-address"])
+	}
+	forwardDelay := uint32(1500)
+	bridge := &IfBridge{IfCommon: IfCommon{Name: "br0", Mac: "52:54:00:12:34:56"}, ForwardDelay: &forwardDelay}
+	_, settings, _ := RenderNmcliIf(bridge)
+	if !NmcliSettingsMatch(settings, fields) {
+		t.Error("expected the profile to match")
+	}
+	bridge.VlanFiltering = true
+	_, settings, _ = RenderNmcliIf(bridge)
+	if NmcliSettingsMatch(settings, fields) {
+		t.Error("expected a changed vlan filtering not to match")
+	}
+	bridge.VlanFiltering = false
+	bridge.State = "down"
+	_, settings, _ = RenderNmcliIf(bridge)
+	if NmcliSettingsMatch(settings, fields) {
+		t.Error("expected a profile of a down interface not to autoconnect")
+	}
+}
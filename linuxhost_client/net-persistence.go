@@ -0,0 +1,187 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Persistence writes the configuration of interfaces and addresses to the
+// files of a network manager so they survive a reboot. It does not apply
+// anything, the live state is set with ip.
+type Persistence interface {
+	SaveIf(connectedClient *SSHClientContext, iface IsIf) error
+	RemoveIf(connectedClient *SSHClientContext, iface IsIf) error
+	// IfDiff returns the file or profile that no longer matches the
+	// interface, empty if all of them do
+	IfDiff(connectedClient *SSHClientContext, iface IsIf) (string, error)
+
+	SaveAddress(connectedClient *SSHClientContext, dev string, address string) error
+	RemoveAddress(connectedClient *SSHClientContext, dev string, address string) error
+	AddressMatches(connectedClient *SSHClientContext, dev string, address string) (bool, error)
+}
+
+// GetPersistence returns the backend by name, nil for no persistence
+func GetPersistence(hostData *HostData, name string) (Persistence, error) {
+	switch name {
+	case "", "none":
+		return nil, nil
+	case "networkd":
+		return networkdPersistence, nil
+	case "netplan":
+		return &netplanPersistence{apply: hostData.NetplanApply}, nil
+	case "nmcli":
+		return &nmcliPersistence{}, nil
+	case "ifupdown":
+		return &ifupdownPersistence{}, nil
+	}
+	return nil, fmt.Errorf("unknown persistence backend %q", name)
+}
+
+// PersistedFiles maps a file path to its content
+type PersistedFiles map[string]string
+
+// filePersistence is a backend that renders one or more files per interface
+// or address, saving writes them and removing deletes them.
+type filePersistence struct {
+	renderIf      func(connectedClient *SSHClientContext, iface IsIf) (PersistedFiles, error)
+	renderAddress func(connectedClient *SSHClientContext, dev string, address string) (PersistedFiles, error)
+}
+
+var _ Persistence = &filePersistence{}
+
+func (p *filePersistence) SaveIf(connectedClient *SSHClientContext, iface IsIf) error {
+	if err := checkPersistable(iface); err != nil {
+		return err
+	}
+	files, err := p.renderIf(connectedClient, iface)
+	if err != nil {
+		return err
+	}
+	return writePersistedFiles(connectedClient, files)
+}
+
+func (p *filePersistence) RemoveIf(connectedClient *SSHClientContext, iface IsIf) error {
+	files, err := p.renderIf(connectedClient, iface)
+	if err != nil {
+		return err
+	}
+	return removePersistedFiles(connectedClient, files)
+}
+
+func (p *filePersistence) IfDiff(connectedClient *SSHClientContext, iface IsIf) (string, error) {
+	files, err := p.renderIf(connectedClient, iface)
+	if err != nil {
+		return "", err
+	}
+	return persistedFilesDiff(connectedClient, files)
+}
+
+func (p *filePersistence) SaveAddress(connectedClient *SSHClientContext, dev string, address string) error {
+	files, err := p.renderAddress(connectedClient, dev, address)
+	if err != nil {
+		return err
+	}
+	return writePersistedFiles(connectedClient, files)
+}
+
+func (p *filePersistence) RemoveAddress(connectedClient *SSHClientContext, dev string, address string) error {
+	files, err := p.renderAddress(connectedClient, dev, address)
+	if err != nil {
+		return err
+	}
+	return removePersistedFiles(connectedClient, files)
+}
+
+func (p *filePersistence) AddressMatches(connectedClient *SSHClientContext, dev string, address string) (bool, error) {
+	files, err := p.renderAddress(connectedClient, dev, address)
+	if err != nil {
+		return false, err
+	}
+	return persistedFilesMatch(connectedClient, files)
+}
+
+// checkPersistable rejects interfaces the backends can't describe
+func checkPersistable(iface IsIf) error {
+	if iface.GetCommon().Netns != "" {
+		return fmt.Errorf("interface %s is in network namespace %s, persistence is only supported in the root namespace", iface.GetCommon().Name, iface.GetCommon().Netns)
+	}
+	return nil
+}
+
+func (files PersistedFiles) paths() []string {
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func writePersistedFiles(connectedClient *SSHClientContext, files PersistedFiles) error {
+	for _, p := range files.paths() {
+		cmd := fmt.Sprintf("sudo mkdir -p %s && cat << 'LINUXHOST_EOF' | sudo tee %s > /dev/null\n%sLINUXHOST_EOF", shellQuote(path.Dir(p)), shellQuote(p), files[p])
+		if _, err := connectedClient.ExecuteCommand(cmd); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func removePersistedFiles(connectedClient *SSHClientContext, files PersistedFiles) error {
+	if len(files) == 0 {
+		return nil
+	}
+	quoted := []string{}
+	for _, p := range files.paths() {
+		quoted = append(quoted, shellQuote(p))
+	}
+	_, err := connectedClient.ExecuteCommand("sudo rm -f " + strings.Join(quoted, " "))
+	return err
+}
+
+func persistedFilesMatch(connectedClient *SSHClientContext, files PersistedFiles) (bool, error) {
+	differs, err := persistedFilesDiff(connectedClient, files)
+	return err == nil && differs == "", err
+}
+
+// persistedFilesDiff returns the first file whose content differs
+func persistedFilesDiff(connectedClient *SSHClientContext, files PersistedFiles) (string, error) {
+	for _, p := range files.paths() {
+		content, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo cat %s 2>/dev/null || true", shellQuote(p)))
+		if err != nil {
+			return "", err
+		}
+		if content != files[p] {
+			return p, nil
+		}
+	}
+	return "", nil
+}
+
+// iniFile builds the systemd style key=value files, sections keep the order
+// they were first used in
+type iniFile struct {
+	sections []string
+	entries  map[string][]string
+}
+
+func newIniFile() *iniFile {
+	return &iniFile{entries: map[string][]string{}}
+}
+
+func (f *iniFile) Set(section string, key string, value string) {
+	if _, ok := f.entries[section]; !ok {
+		f.sections = append(f.sections, section)
+	}
+	f.entries[section] = append(f.entries[section], key+"="+value)
+}
+
+func (f *iniFile) String() string {
+	blocks := []string{}
+	for _, section := range f.sections {
+		blocks = append(blocks, "["+section+"]\n"+strings.Join(f.entries[section], "\n")+"\n")
+	}
+	return strings.Join(blocks, "\n")
+}
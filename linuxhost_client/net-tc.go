@@ -0,0 +1,201 @@
+package linuxhost_client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// TcQdisc is a queueing discipline. Parent is root, ingress, clsact or the
+// class it's attached to like 1:10, Options are the tc parameters of Kind
+// like `delay 100ms loss 1%`. Handle is assigned by the kernel if empty.
+type TcQdisc struct {
+	Dev     string
+	Parent  string
+	Handle  string
+	Kind    string
+	Options string
+}
+
+// TcClass is a class of a classful qdisc like htb
+type TcClass struct {
+	Dev     string
+	Parent  string
+	ClassId string
+	Kind    string
+	Options string
+}
+
+// TcObject is a qdisc or class as listed by `tc -j`
+type TcObject map[string]any
+
+// tcVolatileKeys change without the object being changed: the reference
+// count of a qdisc and the qdisc attached to a class
+var tcVolatileKeys = []string{"refcnt", "leaf"}
+
+const tcMissing = "### missing"
+
+// TcIngressParent is the parent ingress and clsact qdiscs are listed with
+const TcIngressParent = "ffff:fff1"
+
+// TcSpecialParent reports whether parent is a hook rather than a class, the
+// qdisc kind of ingress and clsact is named like their parent
+func TcSpecialParent(parent string) bool {
+	return parent == "root" || parent == "ingress" || parent == "clsact"
+}
+
+func tcParentArgs(parent string) string {
+	if TcSpecialParent(parent) {
+		return parent
+	}
+	return "parent " + shellQuote(parent)
+}
+
+// SetTcQdisc creates the qdisc or replaces the one at its parent
+func SetTcQdisc(connectedClient *SSHClientContext, qdisc *TcQdisc) error {
+	cmd := fmt.Sprintf("sudo tc qdisc replace dev %s %s", shellQuote(qdisc.Dev), tcParentArgs(qdisc.Parent))
+	if qdisc.Handle != "" {
+		cmd = cmd + " handle " + shellQuote(qdisc.Handle)
+	}
+	if qdisc.Parent != "ingress" && qdisc.Parent != "clsact" {
+		cmd = cmd + " " + shellQuote(qdisc.Kind)
+	}
+	if qdisc.Options != "" {
+		cmd = cmd + " " + qdisc.Options
+	}
+	_, err := connectedClient.ExecuteCommand(cmd)
+	return err
+}
+
+// DeleteTcQdisc deletes the qdisc at parent, the kernel restores the default
+// root qdisc
+func DeleteTcQdisc(connectedClient *SSHClientContext, dev string, parent string) error {
+	_, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo tc qdisc del dev %s %s", shellQuote(dev), tcParentArgs(parent)))
+	return err
+}
+
+// SetTcClass adds the class or, with change, updates its options
+func SetTcClass(connectedClient *SSHClientContext, class *TcClass, change bool) error {
+	verb := "add"
+	if change {
+		verb = "change"
+	}
+	cmd := fmt.Sprintf("sudo tc class %s dev %s parent %s classid %s %s", verb, shellQuote(class.Dev), shellQuote(class.Parent), shellQuote(class.ClassId), shellQuote(class.Kind))
+	if class.Options != "" {
+		cmd = cmd + " " + class.Options
+	}
+	_, err := connectedClient.ExecuteCommand(cmd)
+	return err
+}
+
+func DeleteTcClass(connectedClient *SSHClientContext, dev string, classId string) error {
+	_, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo tc class del dev %s classid %s", shellQuote(dev), shellQuote(classId)))
+	return err
+}
+
+// ReadTcObjects lists the qdiscs or classes of dev, what is either "qdisc"
+// or "class". They're nil if the interface doesn't exist.
+func ReadTcObjects(connectedClient *SSHClientContext, what string, dev string) ([]TcObject, error) {
+	name := shellQuote(dev)
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("[ -e /sys/class/net/%s ] || { echo '%s'; exit 0; }; tc -j %s show dev %s", name, tcMissing, what, name))
+	if err != nil {
+		return nil, err
+	}
+	return ParseTcObjects(result)
+}
+
+// ParseTcObjects parses the output of `tc -j qdisc show` or `tc -j class show`
+func ParseTcObjects(output string) ([]TcObject, error) {
+	output = strings.TrimSpace(output)
+	if output == tcMissing {
+		return nil, nil
+	}
+	objects := []TcObject{}
+	if output == "" {
+		return objects, nil
+	}
+	if err := json.Unmarshal([]byte(output), &objects); err != nil {
+		return nil, fmt.Errorf("failed to parse tc output: %w", err)
+	}
+	return objects, nil
+}
+
+func (o TcObject) String(key string) string {
+	value, _ := o[key].(string)
+	return value
+}
+
+// FindTcQdisc returns the qdisc at parent
+func FindTcQdisc(qdiscs []TcObject, parent string) TcObject {
+	for _, qdisc := range qdiscs {
+		switch parent {
+		case "root":
+			if root, _ := qdisc["root"].(bool); root {
+				return qdisc
+			}
+		case "ingress", "clsact":
+			if qdisc.String("kind") == parent && qdisc.String("parent") == TcIngressParent {
+				return qdisc
+			}
+		default:
+			if qdisc.String("parent") == parent {
+				return qdisc
+			}
+		}
+	}
+	return nil
+}
+
+// FindTcClass returns the class with classId
+func FindTcClass(classes []TcObject, classId string) TcObject {
+	for _, class := range classes {
+		if class.String("handle") == classId {
+			return class
+		}
+	}
+	return nil
+}
+
+// Fingerprint is the object without its volatile keys, to compare it to the
+// object listed after it was applied
+func (o TcObject) Fingerprint() []byte {
+	stable := TcObject{}
+	for key, value := range o {
+		stable[key] = value
+	}
+	for _, key := range tcVolatileKeys {
+		delete(stable, key)
+	}
+	// Maps marshal with sorted keys
+	result, _ := json.Marshal(stable)
+	return result
+}
+
+// ReadTcOptions returns the options of the qdisc or class with handle as tc
+// prints them, to show what changed
+func ReadTcOptions(connectedClient *SSHClientContext, what string, dev string, handle string) (string, error) {
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("tc %s show dev %s", what, shellQuote(dev)))
+	if err != nil {
+		return "", err
+	}
+	return ParseTcOptions(result, handle), nil
+}
+
+// ParseTcOptions finds the line of handle in the output of `tc qdisc show`
+// or `tc class show` and returns what follows the qdisc or class itself
+func ParseTcOptions(output string, handle string) string {
+	skip := map[string]int{"dev": 2, "root": 1, "parent": 2, "refcnt": 2, "leaf": 2}
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		// "qdisc netem 8001: root refcnt 2 limit 1000 delay 100ms"
+		if len(fields) < 3 || fields[2] != handle {
+			continue
+		}
+		fields = fields[3:]
+		for len(fields) > 0 && skip[fields[0]] > 0 && len(fields) >= skip[fields[0]] {
+			fields = fields[skip[fields[0]]:]
+		}
+		return strings.Join(fields, " ")
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+var tcQdiscOutput string = `[{"kind":"htb","handle":"1:","root":true,"refcnt":2,"options":{"r2q":10,"default":"0x10","direct_packets_stat":0,"direct_qlen":1000}},{"kind":"netem","handle":"10:","parent":"1:10","options":{"limit":1000,"delay":{"delay":0.1,"jitter":0.01,"correlation":0},"ecn":false,"gap":0}},{"kind":"clsact","handle":"ffff:","parent":"ffff:fff1","options":{}}]`
+
+var tcClassOutput string = `[{"class":"htb","handle":"1:1","root":true,"prio":0,"rate":12500000,"ceil":12500000,"burst":1600,"cburst":1600},{"class":"htb","handle":"1:10","parent":"1:1","leaf":"10:","prio":0,"rate":1250000,"ceil":12500000,"burst":1600,"cburst":1600}]`
+
+func TestParseTcObjects(t *testing.T) {
+	qdiscs, err := ParseTcObjects(tcQdiscOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	if root := FindTcQdisc(qdiscs, "root"); root == nil || root.String("kind") != "htb" || root.String("handle") != "1:" {
+		t.Errorf("Unexpected root qdisc %v", root)
+	}
+	if netem := FindTcQdisc(qdiscs, "1:10"); netem == nil || netem.String("handle") != "10:" {
+		t.Errorf("Unexpected class qdisc %v", netem)
+	}
+	if FindTcQdisc(qdiscs, "clsact") == nil || FindTcQdisc(qdiscs, "ingress") != nil {
+		t.Errorf("Expected only a clsact qdisc")
+	}
+
+	classes, err := ParseTcObjects(tcClassOutput)
+	if err != nil {
+		t.Fatalf("Unexpected error %v", err)
+	}
+	class := FindTcClass(classes, "1:10")
+	if class == nil || class.String("parent") != "1:1" {
+		t.Fatalf("Unexpected class %v", class)
+	}
+	before := string(class.Fingerprint())
+	class["leaf"] = "20:"
+	if string(class.Fingerprint()) != before {
+		t.Errorf("Expected the attached qdisc not to change the fingerprint")
+	}
+	class["rate"] = 2500000
+	if string(class.Fingerprint()) == before {
+		t.Errorf("Expected the rate to change the fingerprint")
+	}
+
+	if missing, err := ParseTcObjects(tcMissing + "\n"); missing != nil || err != nil {
+		t.Errorf("Expected nil for a missing interface, got %v %v", missing, err)
+	}
+}
+
+func TestParseTcOptions(t *testing.T) {
+	qdiscs := `qdisc htb 1: root refcnt 2 r2q 10 default 0x10 direct_packets_stat 0 direct_qlen 1000
+qdisc netem 10: parent 1:10 limit 1000 delay 100ms  10ms
+qdisc clsact ffff: parent ffff:fff1
+`
+	if options := ParseTcOptions(qdiscs, "10:"); options != "limit 1000 delay 100ms 10ms" {
+		t.Errorf("Unexpected options %q", options)
+	}
+	if options := ParseTcOptions(qdiscs, "1:"); options != "r2q 10 default 0x10 direct_packets_stat 0 direct_qlen 1000" {
+		t.Errorf("Unexpected options %q", options)
+	}
+	classes := "class htb 1:10 parent 1:1 leaf 10: prio 0 rate 10Mbit ceil 100Mbit burst 1600b cburst 1600b\n"
+	if options := ParseTcOptions(classes, "1:10"); options != "prio 0 rate 10Mbit ceil 100Mbit burst 1600b cburst 1600b" {
+		t.Errorf("Unexpected options %q", options)
+	}
+}
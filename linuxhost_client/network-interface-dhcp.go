@@ -2,76 +2,331 @@ package linuxhost_client
 
 import (
 	"fmt"
+	"net"
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// DhcpClients are the supported DHCP clients
+var DhcpClients = []string{"dhclient", "dhcpcd", "udhcpc", "networkd"}
+
+// DhcpLease is the lease obtained by the DHCP client of an interface
+type DhcpLease struct {
+	// Address is the leased address with its prefix length
+	Address    string
+	Gateway    string
+	DnsServers []string
+	// Expiry is the RFC 3339 time the lease expires, empty if unknown
+	Expiry string
+}
+
+const (
+	networkdDhcpDirectory = "/run/systemd/network"
+	udhcpcScript          = "/etc/udhcpc/linuxhost.script"
+)
+
+func dhclientArgs(adapterName string) string {
+	return fmt.Sprintf("-4 -v -i -pf /run/dhclient.%[1]s.pid -lf /var/lib/dhcp/dhclient.%[1]s.leases -I -df /var/lib/dhcp/dhclient6.%[1]s.leases %[1]s", adapterName)
+}
+
+func networkdDhcpFile(adapterName string) string {
+	return fmt.Sprintf("%s/10-linuxhost-dhcp-%s.network", networkdDhcpDirectory, adapterName)
+}
+
+// udhcpc has no lease file, the script records the lease and then runs the
+// default script configuring the interface
+const udhcpcScriptContent = `#!/bin/sh
+# Managed by terraform-provider-linuxhost
+leasefile=/run/udhcpc.$interface.lease
+case "$1" in
+bound|renew)
+	printf 'ip=%s\nsubnet=%s\nrouter=%s\ndns=%s\nlease=%s\ntimestamp=%s\n' "$ip" "$subnet" "$router" "$dns" "$lease" "$(date +%s)" > "$leasefile"
+	;;
+deconfig)
+	rm -f "$leasefile"
+	;;
+esac
+for script in /usr/share/udhcpc/default.script /etc/udhcpc/default.script; do
+	[ -x "$script" ] && exec "$script" "$@"
+done
+`
+
+// dhcpCommand is the script starting or stopping the client on the interface.
+// Clients fork once they have a lease, their output goes to a log that is
+// shown when starting fails.
+func dhcpCommand(adapterName string, dhcpMode string, enabled bool) (string, error) {
+	log := fmt.Sprintf("/run/linuxhost-dhcp.%s.log", adapterName)
+	run := func(cmd string) string {
+		return fmt.Sprintf("%s </dev/null >%s 2>&1 || { cat %s; exit 1; }\n", cmd, log, log)
+	}
+	require := func(binary string) string {
+		return fmt.Sprintf("command -v %[1]s >/dev/null || { echo '%[1]s is not installed on the host'; exit 1; }\n", binary)
+	}
+	name := shellQuote(adapterName)
+
+	script := ""
+	switch dhcpMode {
+	case "dhclient":
+		if enabled {
+			script = require("dhclient") + "mkdir -p /var/lib/dhcp\n" + run("dhclient "+dhclientArgs(adapterName))
+		} else {
+			script = run("dhclient -r " + dhclientArgs(adapterName))
+		}
+	case "dhcpcd":
+		if enabled {
+			script = require("dhcpcd") + run("dhcpcd -4 "+name)
+		} else {
+			script = run("dhcpcd -4 -k " + name)
+		}
+	case "udhcpc":
+		pidFile := fmt.Sprintf("/run/udhcpc.%s.pid", adapterName)
+		if enabled {
+			script = require("udhcpc") +
+				fmt.Sprintf("mkdir -p %s\ncat > %s <<'LINUXHOST_EOF'\n%sLINUXHOST_EOF\nchmod 755 %s\n", path.Dir(udhcpcScript), udhcpcScript, udhcpcScriptContent, udhcpcScript) +
+				run(fmt.Sprintf("udhcpc -b -R -i %s -p %s -s %s", name, pidFile, udhcpcScript))
+		} else {
+			// -R releases the lease when the client exits
+			script = fmt.Sprintf("if [ -f %[1]s ]; then kill $(cat %[1]s) 2>/dev/null; rm -f %[1]s; fi\nrm -f /run/udhcpc.%[2]s.lease\n", pidFile, adapterName)
+		}
+	case "networkd":
+		file := networkdDhcpFile(adapterName)
+		if enabled {
+			script = require("networkctl") +
+				fmt.Sprintf("mkdir -p %s\nprintf '[Match]\\nName=%%s\\n\\n[Network]\\nDHCP=ipv4\\n' %s > %s\n", networkdDhcpDirectory, name, file) +
+				run("networkctl reload") + run("networkctl reconfigure "+name)
+		} else {
+			script = fmt.Sprintf("rm -f %s\n", file) + run("networkctl reload") + run("networkctl reconfigure "+name)
+		}
+	default:
+		return "", fmt.Errorf("unknown DHCP client %q, supported are %s", dhcpMode, strings.Join(DhcpClients, ", "))
+	}
+	return "PATH=$PATH:/sbin:/usr/sbin\n" + script, nil
+}
+
+// SetDhcp starts the DHCP client dhcpMode on the interface, or stops it and
+// releases the lease
 func SetDhcp(connectedClient *SSHClientContext, adapterName string, dhcpMode string, enabled bool) error {
-	// fmt.Println("Enabling DHCP")
-	cmd := fmt.Sprintf("sudo dhclient -4 -v -i -pf /run/dhclient.%s.pid -lf /var/lib/dhcp/dhclient.%s.leases -I -df /var/lib/dhcp/dhclient6.%s.leases %%s %s", adapterName, adapterName, adapterName, adapterName)
-	// fmt.Println("Base cmd: ", cmd)
-	if enabled {
-		cmd = fmt.Sprintf(cmd+" &", "")
-	} else {
-		cmd = fmt.Sprintf(cmd, " -r")
-	}
-	// fmt.Println("Cmd: ", cmd)
-	result, err := connectedClient.ExecuteCommand(cmd)
+	script, err := dhcpCommand(adapterName, dhcpMode, enabled)
+	if err != nil {
+		return err
+	}
+	result, err := connectedClient.ExecuteCommand("sudo sh -c " + shellQuote(script))
 	if err != nil {
-		fmt.Println("Error!!" + err.Error())
 		return err
 	}
 	fmt.Println(result)
 	return nil
 }
 
+// RefreshDhcp sets the DHCP client and lease of the adapters
 func RefreshDhcp(hostData *HostData, adapters []*AdapterInfo) error {
-	cmd := "sudo ps -ef | grep dhclient"
-	fmt.Println("cmd: ", cmd)
+	cmd := fmt.Sprintf("ps -eo args=; echo '### networkd'; ls %s 2>/dev/null || true", networkdDhcpDirectory)
 	result, err := hostData.Client.ExecuteCommand(cmd)
 	if err != nil {
 		return err
 	}
-	fmt.Println("SSH Result (refresh DHCP): ", result)
-	adaptersSlice := AdapterInfoListToMap(adapters)
-	ParseDhclient(result, &adaptersSlice)
+	clients := ParseDhcpClients(result)
+	for _, adapter := range adapters {
+		client, found := clients[adapter.Name]
+		if !found {
+			continue
+		}
+		adapter.DHCP = &client
+		lease, err := ReadDhcpLease(hostData.Client, adapter.Name, client)
+		if err != nil {
+			return err
+		}
+		adapter.DHCPLease = lease
+	}
 	return nil
 }
 
-func ParseDhclient(stdout string, adapters *map[string]*AdapterInfo) {
-	dhclientRegex := regexp.MustCompile(`dhclient.* (.*)$`)
-
-	// Split output into lines
-	lines := strings.Split(stdout, "\n")
-
-	// _adapters := *adapters
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		fmt.Println("Checking line", line)
-		// Match adapter lines
-		if match := dhclientRegex.FindStringSubmatch(line); match != nil {
-			fmt.Println("Checking for adapter", match[1])
-			adapterMap := *adapters
-			adapter, found := adapterMap[match[1]]
-			// adapter2, found2 := adapterMap[match[1]]
-			// _ = found2
-			fmt.Println("found?", found)
-			if !found {
-				continue
+var networkdDhcpFileRegex = regexp.MustCompile(`^10-linuxhost-dhcp-(.+)\.network$`)
+
+// ParseDhcpClients maps the interfaces to their DHCP client from the process
+// list, followed by the files in the networkd runtime directory
+func ParseDhcpClients(stdout string) map[string]string {
+	clients := map[string]string{}
+	networkd := false
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if line == "### networkd" {
+			networkd = true
+			continue
+		}
+		if networkd {
+			if match := networkdDhcpFileRegex.FindStringSubmatch(fields[0]); match != nil {
+				clients[match[1]] = "networkd"
+			}
+			continue
+		}
+		if fields[0] == "sudo" {
+			fields = fields[1:]
+		}
+		if len(fields) < 2 {
+			continue
+		}
+		last := fields[len(fields)-1]
+		switch path.Base(fields[0]) {
+		case "dhclient":
+			// Releasing runs dhclient -r as well
+			if !strings.HasPrefix(last, "-") && !contains(fields, "-r") {
+				clients[last] = "dhclient"
 			}
-			// fmt.Println("CCCC", adapter, adapter2)
-			fmt.Println(adapter)
-			// fmt.Println(adapter2)
-			fmt.Printf("Pointer address 1: %p\n", adapter)
-			// fmt.Printf("Pointer address 2: %p\n", adapter2)
-			fmt.Println("Found: ", adapter)
-			S := "dhclient"
+		case "dhcpcd:":
+			// dhcpcd 9 names its per interface processes "dhcpcd: eth0 [ip4]"
+			if !strings.HasPrefix(fields[1], "[") {
+				clients[fields[1]] = "dhcpcd"
+			}
+		case "dhcpcd":
+			if !strings.HasPrefix(last, "-") && !contains(fields, "-k") {
+				clients[last] = "dhcpcd"
+			}
+		case "udhcpc":
+			for i, field := range fields[:len(fields)-1] {
+				if field == "-i" {
+					clients[fields[i+1]] = "udhcpc"
+				}
+			}
+		}
+	}
+	return clients
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
 
-			fmt.Println("Set dhclient!!!")
-			adapter.DHCP = &S
-			// (_adapters)[match[1]] = adapter
+// ReadDhcpLease reads the lease file of the client, nil if there is no lease
+func ReadDhcpLease(connectedClient *SSHClientContext, adapterName string, dhcpMode string) (*DhcpLease, error) {
+	name := shellQuote(adapterName)
+	switch dhcpMode {
+	case "dhclient":
+		result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo cat /var/lib/dhcp/dhclient.%s.leases 2>/dev/null || true", adapterName))
+		if err != nil {
+			return nil, err
+		}
+		return ParseDhclientLease(result), nil
+	case "dhcpcd":
+		cmd := fmt.Sprintf("sudo dhcpcd -4 -U %[1]s 2>/dev/null; stat -c 'lease_timestamp=%%Y' /var/lib/dhcpcd/%[2]s.lease /var/lib/dhcpcd/dhcpcd-%[2]s.lease 2>/dev/null; true", name, adapterName)
+		result, err := connectedClient.ExecuteCommand(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return dhcpLeaseFromValues(ParseDhcpValues(result), "ip_address", "subnet_cidr", "routers", "domain_name_servers", "dhcp_lease_time", "lease_timestamp"), nil
+	case "udhcpc":
+		result, err := connectedClient.ExecuteCommand(fmt.Sprintf("cat /run/udhcpc.%s.lease 2>/dev/null || true", adapterName))
+		if err != nil {
+			return nil, err
+		}
+		return dhcpLeaseFromValues(ParseDhcpValues(result), "ip", "subnet", "router", "dns", "lease", "timestamp"), nil
+	case "networkd":
+		cmd := fmt.Sprintf("f=/run/systemd/netif/leases/$(cat /sys/class/net/%s/ifindex 2>/dev/null); if [ -f \"$f\" ]; then cat \"$f\"; stat -c 'TIMESTAMP=%%Y' \"$f\"; fi", name)
+		result, err := connectedClient.ExecuteCommand(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return dhcpLeaseFromValues(ParseDhcpValues(result), "ADDRESS", "NETMASK", "ROUTER", "DNS", "LIFETIME", "TIMESTAMP"), nil
+	}
+	return nil, fmt.Errorf("unknown DHCP client %q", dhcpMode)
+}
+
+// ParseDhcpValues parses KEY=value lines, the values may be single quoted
+func ParseDhcpValues(stdout string) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(stdout, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || key == "" || strings.HasPrefix(key, "#") {
+			continue
+		}
+		values[key] = strings.Trim(value, "'")
+	}
+	return values
+}
+
+// prefixLength accepts a dotted netmask or a prefix length
+func prefixLength(mask string) string {
+	if !strings.Contains(mask, ".") {
+		return mask
+	}
+	ip := net.ParseIP(mask).To4()
+	if ip == nil {
+		return ""
+	}
+	ones, _ := net.IPMask(ip).Size()
+	return strconv.Itoa(ones)
+}
+
+func dhcpLeaseFromValues(values map[string]string, address, mask, router, dns, lifetime, timestamp string) *DhcpLease {
+	if values[address] == "" {
+		return nil
+	}
+	lease := &DhcpLease{
+		Address:    values[address],
+		DnsServers: strings.Fields(values[dns]),
+	}
+	if prefix := prefixLength(values[mask]); prefix != "" {
+		lease.Address = lease.Address + "/" + prefix
+	}
+	if routers := strings.Fields(values[router]); len(routers) > 0 {
+		lease.Gateway = routers[0]
+	}
+	seconds, err1 := strconv.ParseInt(values[lifetime], 10, 64)
+	start, err2 := strconv.ParseInt(values[timestamp], 10, 64)
+	if err1 == nil && err2 == nil {
+		lease.Expiry = time.Unix(start+seconds, 0).UTC().Format(time.RFC3339)
+	}
+	return lease
+}
+
+var (
+	dhclientLeaseRegex  = regexp.MustCompile(`(?s)lease \{(.*?)\}`)
+	dhclientOptionRegex = regexp.MustCompile(`^\s*(option )?(\S+) (.*);`)
+)
+
+// ParseDhclientLease returns the most recent lease of a dhclient lease file
+func ParseDhclientLease(content string) *DhcpLease {
+	leases := dhclientLeaseRegex.FindAllStringSubmatch(content, -1)
+	if len(leases) == 0 {
+		return nil
+	}
+	values := map[string]string{}
+	for _, line := range strings.Split(leases[len(leases)-1][1], "\n") {
+		if match := dhclientOptionRegex.FindStringSubmatch(line); match != nil {
+			values[match[2]] = strings.Trim(match[3], `"`)
+		}
+	}
+	lease := dhcpLeaseFromValues(values, "fixed-address", "subnet-mask", "routers", "", "", "")
+	if lease == nil {
+		return nil
+	}
+	lease.Gateway = strings.Split(lease.Gateway, ",")[0]
+	for _, server := range strings.Split(values["domain-name-servers"], ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			lease.DnsServers = append(lease.DnsServers, server)
+		}
+	}
+	// "expire 4 2026/10/22 10:00:00;" in UTC, or "expire epoch 1792663200; # ..."
+	expire := strings.Fields(strings.SplitN(values["expire"], "#", 2)[0])
+	if len(expire) == 2 && expire[0] == "epoch" {
+		if seconds, err := strconv.ParseInt(expire[1], 10, 64); err == nil {
+			lease.Expiry = time.Unix(seconds, 0).UTC().Format(time.RFC3339)
+		}
+	} else if len(expire) == 3 {
+		if t, err := time.Parse("2006/01/02 15:04:05", expire[1]+" "+expire[2]); err == nil {
+			lease.Expiry = t.UTC().Format(time.RFC3339)
 		}
 	}
-	println("Pointers!", adapters)
+	return lease
 }
@@ -0,0 +1,87 @@
+package linuxhost_client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDhcpClients(t *testing.T) {
+	output := `/sbin/init
+dhclient -4 -v -i -pf /run/dhclient.eth0.pid -lf /var/lib/dhcp/dhclient.eth0.leases -I -df /var/lib/dhcp/dhclient6.eth0.leases eth0
+dhcpcd: [manager] [ip4]
+dhcpcd: eth1 [ip4]
+udhcpc -b -R -i eth2 -p /run/udhcpc.eth2.pid -s /etc/udhcpc/linuxhost.script
+grep dhclient
+### networkd
+10-linuxhost-dhcp-eth3.network
+50-other.network
+`
+	expected := map[string]string{
+		"eth0": "dhclient",
+		"eth1": "dhcpcd",
+		"eth2": "udhcpc",
+		"eth3": "networkd",
+	}
+	if clients := ParseDhcpClients(output); !reflect.DeepEqual(clients, expected) {
+		t.Errorf("unexpected clients %v, expected %v", clients, expected)
+	}
+}
+
+func TestParseDhclientLease(t *testing.T) {
+	content := `lease {
+  interface "eth0";
+  fixed-address 10.0.0.4;
+  option subnet-mask 255.255.255.0;
+  option routers 10.0.0.1;
+  expire 4 2026/10/22 10:00:00;
+}
+lease {
+  interface "eth0";
+  fixed-address 10.0.0.5;
+  option subnet-mask 255.255.255.0;
+  option routers 10.0.0.1,10.0.0.2;
+  option domain-name-servers 1.1.1.1,8.8.8.8;
+  renew 4 2026/10/22 16:00:00;
+  expire epoch 1792663200; # Thu Oct 22 10:00:00 2026
+}
+`
+	expected := &DhcpLease{
+		Address:    "10.0.0.5/24",
+		Gateway:    "10.0.0.1",
+		DnsServers: []string{"1.1.1.1", "8.8.8.8"},
+		Expiry:     "2026-10-22T10:00:00Z",
+	}
+	if lease := ParseDhclientLease(content); !reflect.DeepEqual(lease, expected) {
+		t.Errorf("unexpected lease %+v, expected %+v", lease, expected)
+	}
+	if lease := ParseDhclientLease(""); lease != nil {
+		t.Errorf("expected no lease, got %+v", lease)
+	}
+}
+
+func TestDhcpLeaseFromValues(t *testing.T) {
+	output := `ip_address='10.0.1.5'
+subnet_cidr='24'
+routers='10.0.1.1'
+domain_name_servers='10.0.1.1 9.9.9.9'
+dhcp_lease_time='3600'
+lease_timestamp=1792659600
+`
+	expected := &DhcpLease{
+		Address:    "10.0.1.5/24",
+		Gateway:    "10.0.1.1",
+		DnsServers: []string{"10.0.1.1", "9.9.9.9"},
+		Expiry:     "2026-10-22T10:00:00Z",
+	}
+	lease := dhcpLeaseFromValues(ParseDhcpValues(output), "ip_address", "subnet_cidr", "routers", "domain_name_servers", "dhcp_lease_time", "lease_timestamp")
+	if !reflect.DeepEqual(lease, expected) {
+		t.Errorf("unexpected lease %+v, expected %+v", lease, expected)
+	}
+
+	// networkd has a dotted netmask
+	output = "ADDRESS=10.0.2.5\nNETMASK=255.255.0.0\nROUTER=10.0.0.1\n"
+	lease = dhcpLeaseFromValues(ParseDhcpValues(output), "ADDRESS", "NETMASK", "ROUTER", "DNS", "LIFETIME", "TIMESTAMP")
+	if lease.Address != "10.0.2.5/16" || lease.Expiry != "" {
+		t.Errorf("unexpected lease %+v", lease)
+	}
+}
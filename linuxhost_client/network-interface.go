@@ -23,12 +23,33 @@ type IfCommon struct {
 	Mac          string
 	State        string
 	BridgeMember *IfBridgeMember
+	// Netns is a named network namespace or a PID, empty for the root namespace
+	Netns string
+	// Persistence is the backend saving the configuration, empty for none
+	Persistence string
+
+	// Link settings, zero values leave the current value
+	Mtu        uint32
+	TxQueueLen uint32
+	Alias      string
+	Group      string
 
 	IPv4 []models.IPWithSubnet
 	IPv6 []models.IPWithSubnet
 }
 type IfBridgeMember struct {
 	Name string
+
+	// Optional port settings, nil leaves the current value
+	Cost          *uint32
+	Priority      *uint32
+	Hairpin       *bool
+	Learning      *bool
+	Flood         *bool
+	McastFlood    *bool
+	NeighSuppress *bool
+	Isolated      *bool
+	BpduGuard     *bool
 }
 type IsIf interface {
 	GetCommon() *IfCommon
@@ -79,8 +100,12 @@ func InterfaceUpDown(cli *SSHClientContext, Id string, UpDown string) error {
 }
 
 func DeleteInterface(connectedClient *SSHClientContext, Id string) (bool, error) {
+	return DeleteInterfaceInNetns(connectedClient, "", Id)
+}
+
+func DeleteInterfaceInNetns(connectedClient *SSHClientContext, netns string, Id string) (bool, error) {
 	fmt.Println("deleting interface")
-	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sleep 1; sudo ip link del %s", Id))
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sleep 1; %s link del %s", NetnsCommand(netns, "ip"), Id))
 
 	if err != nil {
 		fmt.Println("Error!!!" + err.Error())
@@ -120,6 +145,10 @@ type AdapterInfo struct {
 	Name             string
 	MAC              string
 	Up               bool
+	Mtu              uint32
+	TxQueueLen       uint32
+	Group            string
+	Alias            string
 	IPv4             []models.IPWithSubnet
 	IPv6             []models.IPWithSubnet
 	Type             string
@@ -128,9 +157,13 @@ type AdapterInfo struct {
 	Port             *int32
 	LinkedInterface  *string
 	DHCP             *string
+	DHCPLease        *DhcpLease
 	BridgeInfo       *BridgeInfo
 	VlanInfo         *VlanInfo
+	TunnelInfo       *TunnelInfo
+	VxlanInfo        *VxlanInfo
 	DesignatedBridge *string
+	BridgePortInfo   *BridgePortInfo
 }
 
 type AdapterInfoSlice []*AdapterInfo
@@ -191,8 +224,36 @@ func (l *AdapterInfoSlice) Clear() {
 }
 
 type BridgeInfo struct {
-	BridgeId      string
-	VlanFiltering bool
+	BridgeId        string
+	VlanFiltering   bool
+	VlanDefaultPvid uint32
+	VlanProtocol    string
+
+	// Timers are in centiseconds, as reported by ip
+	Stp              bool
+	ForwardDelay     uint32
+	HelloTime        uint32
+	MaxAge           uint32
+	AgeingTime       uint32
+	Priority         uint32
+	GroupFwdMask     uint32
+	McastSnooping    bool
+	McastQuerier     bool
+	McastIgmpVersion uint32
+	McastMldVersion  uint32
+}
+
+// BridgePortInfo holds the bridge_slave settings of a bridge member
+type BridgePortInfo struct {
+	Cost          uint32
+	Priority      uint32
+	Hairpin       bool
+	Learning      bool
+	Flood         bool
+	McastFlood    bool
+	NeighSuppress bool
+	Isolated      bool
+	BpduGuard     bool
 }
 type VlanInfo struct {
 	Vid    uint32
@@ -228,6 +289,12 @@ type HostData struct {
 	Users      []models.UserModel
 	Groups     []models.GroupModel
 	Hostname   *string
+
+	// NetplanApply runs `netplan apply` after the netplan persistence changed
+	NetplanApply bool
+	// NetworkBackend is "nmcli" if interfaces are NetworkManager profiles,
+	// otherwise they are created with ip
+	NetworkBackend string
 }
 
 func RefreshAdapters(hostData *HostData) (AdapterInfoSlice, error) {
@@ -256,6 +323,33 @@ func ReadAdapters(hostData *HostData) (AdapterInfoSlice, error) {
 	return hostData.Interfaces, nil
 }
 
+// ReadAdaptersInNetns reads the adapters of a network namespace. Namespaces
+// other than the root namespace aren't cached.
+func ReadAdaptersInNetns(hostData *HostData, netns string) (AdapterInfoSlice, error) {
+	if netns == "" {
+		return ReadAdapters(hostData)
+	}
+	result, err := hostData.Client.ExecuteCommand(NetnsCommand(netns, "ip") + " -d a")
+	if err != nil {
+		return nil, err
+	}
+	return ParseAdapters(result), nil
+}
+
+// matchUint32 returns the first submatch of regex as a number, or 0
+func matchUint32(regex *regexp.Regexp, line string) uint32 {
+	match := regex.FindStringSubmatch(line)
+	if match == nil {
+		return 0
+	}
+	v, err := strconv.ParseUint(match[1], 0, 32)
+	if err != nil {
+		fmt.Println("Error converting " + match[0] + " to int")
+		return 0
+	}
+	return uint32(v)
+}
+
 // parseAdapters extracts adapter information from the `ip a` output
 func ParseAdapters(ipOutput string) AdapterInfoSlice {
 	var adapters AdapterInfoSlice
@@ -266,12 +360,47 @@ func ParseAdapters(ipOutput string) AdapterInfoSlice {
 	ipv6Regex := regexp.MustCompile(`inet6 ([a-fA-F0-9:]+)/(\d+)`)
 	macRegex := regexp.MustCompile(`(?:ether|loopback)\s*(([0-9A-Fa-f]{2}[:-]){5}([0-9A-Fa-f]{2}))`)
 	upRegex := regexp.MustCompile(`state (UP|DOWN|UNKNOWN)`)
-	noArpRegex := regexp.MustCompile(`<.*(NOARP).*>`)
+	mtuRegex := regexp.MustCompile(`\bmtu (\d+)`)
+	qlenRegex := regexp.MustCompile(`\bqlen (\d+)`)
+	groupRegex := regexp.MustCompile(`\bgroup (\S+)`)
+	aliasRegex := regexp.MustCompile(`^alias (.*)$`)
+	kindRegex := regexp.MustCompile(`^(dummy|veth|wireguard)\b`)
 	vlanRegex := regexp.MustCompile(`vlan protocol 802\.1Q id (\d+)`)
-	vxlanRegex := regexp.MustCompile(`vxlan id (\d+).*dstport (\d+)`)
+	vxlanRegex := regexp.MustCompile(`vxlan (?:external )?id (\d+).*dstport (\d+)`)
+	vxlanLocalRegex := regexp.MustCompile(`\blocal (\S+)`)
+	vxlanRemoteRegex := regexp.MustCompile(`\bremote (\S+)`)
+	vxlanGroupRegex := regexp.MustCompile(`\bgroup (\S+)`)
+	vxlanDevRegex := regexp.MustCompile(`\bdev (\S+)`)
+	vxlanTtlRegex := regexp.MustCompile(`\bttl (\d+)`)
+	vxlanTosRegex := regexp.MustCompile(`\btos (0x[0-9a-f]+|\d+|inherit)`)
+	vxlanSrcPortRegex := regexp.MustCompile(`\bsrcport (\d+) (\d+)`)
+	vxlanUdpCsumRegex := regexp.MustCompile(`\b(no)?udpcsum\b`)
+	vxlanExternalRegex := regexp.MustCompile(`\bexternal\b`)
+	vxlanNoLearningRegex := regexp.MustCompile(`\bnolearning\b`)
+
+	tunnelRegex := regexp.MustCompile(`^(gre|gretap|ipip|sit) (?:\S+ )?remote (\S+) local (\S+)`)
+	tunnelTtlRegex := regexp.MustCompile(`ttl (\d+|inherit)`)
+	tunnelKeyRegex := regexp.MustCompile(`\b(?:ikey|key) (\S+)`)
+	tunnelPmtuRegex := regexp.MustCompile(`\bnopmtudisc\b`)
 
 	bridgeInfoRegex := regexp.MustCompile(`bridge.*vlan_filtering ([01]).*bridge_id ([^\s]+)`)
+	bridgeVlanPvidRegex := regexp.MustCompile(`vlan_default_pvid (\d+)`)
+	bridgeVlanProtocolRegex := regexp.MustCompile(`vlan_protocol ([^\s]+)`)
+	bridgeStpRegex := regexp.MustCompile(`\bstp_state (\d+)`)
+	bridgeForwardDelayRegex := regexp.MustCompile(`\bforward_delay (\d+)`)
+	bridgeHelloTimeRegex := regexp.MustCompile(`\bhello_time (\d+)`)
+	bridgeMaxAgeRegex := regexp.MustCompile(`\bmax_age (\d+)`)
+	bridgeAgeingTimeRegex := regexp.MustCompile(`\bageing_time (\d+)`)
+	bridgePriorityRegex := regexp.MustCompile(`\bpriority (\d+)`)
+	bridgeGroupFwdMaskRegex := regexp.MustCompile(`\bgroup_fwd_mask ([0-9a-fx]+)`)
+	bridgeMcastSnoopingRegex := regexp.MustCompile(`\bmcast_snooping (\d+)`)
+	bridgeMcastQuerierRegex := regexp.MustCompile(`\bmcast_querier (\d+)`)
+	bridgeMcastIgmpVersionRegex := regexp.MustCompile(`\bmcast_igmp_version (\d+)`)
+	bridgeMcastMldVersionRegex := regexp.MustCompile(`\bmcast_mld_version (\d+)`)
 	bridgeMemberRegex := regexp.MustCompile(`bridge_slave.*designated_bridge ([^\s]+)`)
+	bridgePortCostRegex := regexp.MustCompile(`\bcost (\d+)`)
+	bridgePortPriorityRegex := regexp.MustCompile(`\bpriority (\d+)`)
+	bridgePortFlagRegex := regexp.MustCompile(`\b(hairpin|learning|flood|mcast_flood|neigh_suppress|isolated|guard) (on|off)`)
 
 	// Split output into lines
 	lines := strings.Split(ipOutput, "\n")
@@ -306,10 +435,10 @@ func ParseAdapters(ipOutput string) AdapterInfoSlice {
 				currentAdapter.Up = match[1] != "DOWN"
 			}
 
-			// Match interface type dummy
-			if match := noArpRegex.FindStringSubmatch(line); match != nil {
-				currentAdapter.Type = "dummy"
-			} else {
+			currentAdapter.Mtu = matchUint32(mtuRegex, line)
+			currentAdapter.TxQueueLen = matchUint32(qlenRegex, line)
+			if match := groupRegex.FindStringSubmatch(line); match != nil {
+				currentAdapter.Group = match[1]
 			}
 
 			continue
@@ -334,6 +463,11 @@ func ParseAdapters(ipOutput string) AdapterInfoSlice {
 			})
 		}
 
+		if match := aliasRegex.FindStringSubmatch(line); match != nil {
+			currentAdapter.Alias = match[1]
+			continue
+		}
+
 		// Match MAC
 		if match := macRegex.FindStringSubmatch(line); match != nil {
 			currentAdapter.MAC = match[1]
@@ -342,10 +476,39 @@ func ParseAdapters(ipOutput string) AdapterInfoSlice {
 
 		if match := bridgeMemberRegex.FindStringSubmatch(line); match != nil {
 			currentAdapter.DesignatedBridge = &match[1]
+			port := &BridgePortInfo{
+				Cost:     matchUint32(bridgePortCostRegex, line),
+				Priority: matchUint32(bridgePortPriorityRegex, line),
+			}
+			for _, flag := range bridgePortFlagRegex.FindAllStringSubmatch(line, -1) {
+				on := flag[2] == "on"
+				switch flag[1] {
+				case "hairpin":
+					port.Hairpin = on
+				case "learning":
+					port.Learning = on
+				case "flood":
+					port.Flood = on
+				case "mcast_flood":
+					port.McastFlood = on
+				case "neigh_suppress":
+					port.NeighSuppress = on
+				case "isolated":
+					port.Isolated = on
+				case "guard":
+					port.BpduGuard = on
+				}
+			}
+			currentAdapter.BridgePortInfo = port
 		}
 
 		// Interface specific
 
+		// Match dummy, veth and WireGuard, which have no further details
+		if match := kindRegex.FindStringSubmatch(line); match != nil {
+			currentAdapter.Type = match[1]
+		}
+
 		// Match VLAN
 		if match := vlanRegex.FindStringSubmatch(line); match != nil {
 			currentAdapter.Type = "vlan"
@@ -385,6 +548,63 @@ func ParseAdapters(ipOutput string) AdapterInfoSlice {
 			currentAdapter.Port = &p
 			currentAdapter.Type = "vxlan"
 			fmt.Println("vxlan: " + match[1])
+
+			info := &VxlanInfo{
+				Ttl:      matchUint32(vxlanTtlRegex, line),
+				Learning: !vxlanNoLearningRegex.MatchString(line),
+				External: vxlanExternalRegex.MatchString(line),
+			}
+			if local := vxlanLocalRegex.FindStringSubmatch(line); local != nil {
+				info.Local = local[1]
+			}
+			if remote := vxlanRemoteRegex.FindStringSubmatch(line); remote != nil {
+				info.Remote = remote[1]
+			}
+			if group := vxlanGroupRegex.FindStringSubmatch(line); group != nil {
+				info.Group = group[1]
+			}
+			if dev := vxlanDevRegex.FindStringSubmatch(line); dev != nil {
+				info.Dev = dev[1]
+			}
+			if tos := vxlanTosRegex.FindStringSubmatch(line); tos != nil {
+				if tos[1] == "inherit" {
+					info.Tos = 1
+				} else {
+					info.Tos = matchUint32(vxlanTosRegex, line)
+				}
+			}
+			if srcport := vxlanSrcPortRegex.FindStringSubmatch(line); srcport != nil {
+				min, _ := strconv.ParseUint(srcport[1], 10, 32)
+				max, _ := strconv.ParseUint(srcport[2], 10, 32)
+				info.SrcPortMin = uint32(min)
+				info.SrcPortMax = uint32(max)
+			}
+			if csum := vxlanUdpCsumRegex.FindStringSubmatch(line); csum != nil {
+				info.UdpCsum = csum[1] == ""
+			}
+			currentAdapter.VxlanInfo = info
+		}
+
+		// Match gre, gretap, ipip and sit tunnels
+		if match := tunnelRegex.FindStringSubmatch(line); match != nil {
+			currentAdapter.Type = match[1]
+			tunnel := &TunnelInfo{
+				Kind:     match[1],
+				Remote:   tunnelAddress(match[2]),
+				Local:    tunnelAddress(match[3]),
+				PmtuDisc: !tunnelPmtuRegex.MatchString(line),
+			}
+			if ttl := tunnelTtlRegex.FindStringSubmatch(line); ttl != nil && ttl[1] != "inherit" {
+				v, err := strconv.ParseUint(ttl[1], 10, 32)
+				if err != nil {
+					fmt.Println("Error converting ttl str to int")
+				}
+				tunnel.Ttl = uint32(v)
+			}
+			if key := tunnelKeyRegex.FindStringSubmatch(line); key != nil {
+				tunnel.Key = parseTunnelKey(key[1])
+			}
+			currentAdapter.TunnelInfo = tunnel
 		}
 
 		if match := bridgeInfoRegex.FindStringSubmatch(line); match != nil {
@@ -393,6 +613,22 @@ func ParseAdapters(ipOutput string) AdapterInfoSlice {
 				BridgeId:      match[2],
 				VlanFiltering: match[1] == "1",
 			}
+			currentAdapter.BridgeInfo.VlanDefaultPvid = matchUint32(bridgeVlanPvidRegex, line)
+			if protocol := bridgeVlanProtocolRegex.FindStringSubmatch(line); protocol != nil {
+				currentAdapter.BridgeInfo.VlanProtocol = protocol[1]
+			}
+			info := currentAdapter.BridgeInfo
+			info.Stp = matchUint32(bridgeStpRegex, line) != 0
+			info.ForwardDelay = matchUint32(bridgeForwardDelayRegex, line)
+			info.HelloTime = matchUint32(bridgeHelloTimeRegex, line)
+			info.MaxAge = matchUint32(bridgeMaxAgeRegex, line)
+			info.AgeingTime = matchUint32(bridgeAgeingTimeRegex, line)
+			info.Priority = matchUint32(bridgePriorityRegex, line)
+			info.GroupFwdMask = matchUint32(bridgeGroupFwdMaskRegex, line)
+			info.McastSnooping = matchUint32(bridgeMcastSnoopingRegex, line) != 0
+			info.McastQuerier = matchUint32(bridgeMcastQuerierRegex, line) != 0
+			info.McastIgmpVersion = matchUint32(bridgeMcastIgmpVersionRegex, line)
+			info.McastMldVersion = matchUint32(bridgeMcastMldVersionRegex, line)
 			// currentAdapter.BridgeId = match[2]
 			// fmt.Println("bridgeId: " + match[2])
 		}
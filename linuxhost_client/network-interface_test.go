@@ -0,0 +1,215 @@
+package linuxhost_client
+
+import (
+	"testing"
+)
+
+var ipDetailsOutput string = `1: lo: <LOOPBACK,UP,LOWER_UP> mtu 65536 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/loopback 00:00:00:00:00:00 brd 00:00:00:00:00:00 promiscuity 0  allmulti 0 minmtu 0 maxmtu 0 numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535 tso_max_size 524280 tso_max_segs 65535 gro_max_size 65536
+    inet 127.0.0.1/8 scope host lo
+       valid_lft forever preferred_lft forever
+2: eth0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc fq_codel state UP group default qlen 1000
+    link/ether 52:54:00:12:34:56 brd ff:ff:ff:ff:ff:ff promiscuity 0  allmulti 0 minmtu 68 maxmtu 65535 addrgenmode none numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535 tso_max_size 65536 tso_max_segs 65535 gro_max_size 65536
+    alias uplink to core
+    inet 192.0.2.1/24 brd 192.0.2.255 scope global eth0
+       valid_lft forever preferred_lft forever
+3: br0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1462 qdisc noqueue state UP group default qlen 1000
+    link/ether 4e:2a:3c:11:22:33 brd ff:ff:ff:ff:ff:ff promiscuity 0  allmulti 0 minmtu 68 maxmtu 65535
+    bridge forward_delay 1500 hello_time 200 max_age 2000 ageing_time 30000 stp_state 0 priority 32768 vlan_filtering 0 vlan_protocol 802.1Q bridge_id 8000.4e:2a:3c:11:22:33 designated_root 8000.4e:2a:3c:11:22:33 root_port 0 root_path_cost 0 topology_change 0 topology_change_detected 0 hello_timer    0.00 tcn_timer    0.00 topology_change_timer    0.00 gc_timer  141.15 vlan_default_pvid 1 vlan_stats_enabled 0 vlan_stats_per_port 0 group_fwd_mask 0 group_address 01:80:c2:00:00:00 mcast_snooping 1 no_linklocal_learn 0 mcast_vlan_snooping 0 mcast_router 1 mcast_query_use_ifaddr 0 mcast_querier 0 mcast_hash_elasticity 16 mcast_hash_max 4096 mcast_last_member_count 2 mcast_startup_query_count 2 mcast_last_member_interval 100 mcast_membership_interval 26000 mcast_querier_interval 25500 mcast_query_interval 12500 mcast_query_response_interval 1000 mcast_startup_query_interval 3125 mcast_stats_enabled 0 mcast_igmp_version 2 mcast_mld_version 1 nf_call_iptables 0 nf_call_ip6tables 0 nf_call_arptables 0 addrgenmode eui64 numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535 tso_max_size 65536 tso_max_segs 65535 gro_max_size 65536
+4: gre1@NONE: <POINTOPOINT,NOARP,UP,LOWER_UP> mtu 1472 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/gre 192.0.2.1 peer 198.51.100.1 promiscuity 0  allmulti 0 minmtu 0 maxmtu 0
+    gre remote 198.51.100.1 local 192.0.2.1 ttl 64 tos inherit ikey 0.0.0.42 okey 0.0.0.42 pmtudisc addrgenmode eui64 numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535 tso_max_size 65536 tso_max_segs 65535 gro_max_size 65536
+    inet 10.255.0.1/30 scope global gre1
+       valid_lft forever preferred_lft forever
+5: gretap1@NONE: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1462 qdisc fq_codel master br0 state UNKNOWN group default qlen 1000
+    link/ether 4e:2a:3c:11:22:33 brd ff:ff:ff:ff:ff:ff promiscuity 1  allmulti 1 minmtu 68 maxmtu 0
+    gretap remote 198.51.100.2 local any ttl inherit nopmtudisc
+    bridge_slave state forwarding priority 32 cost 100 hairpin off guard off root_block off fastleave off learning on flood on port_id 0x8001 port_no 0x1 designated_port 32769 designated_cost 0 designated_bridge 8000.4e:2a:3c:11:22:33 designated_root 8000.4e:2a:3c:11:22:33 hold_timer    0.00 message_age_timer    0.00 forward_delay_timer    0.00 topology_change_ack 0 config_pending 0 proxy_arp off proxy_arp_wifi off mcast_router 1 mcast_fast_leave off mcast_flood on bcast_flood on mcast_to_unicast off neigh_suppress off group_fwd_mask 0 group_fwd_mask_str 0x0 vlan_tunnel off isolated off locked off mab off addrgenmode eui64 numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535 tso_max_size 65536 tso_max_segs 65535 gro_max_size 65536
+6: ipip1@NONE: <POINTOPOINT,NOARP,UP,LOWER_UP> mtu 1480 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/ipip 192.0.2.1 peer 198.51.100.3 promiscuity 0  allmulti 0 minmtu 0 maxmtu 0
+    ipip ipip remote 198.51.100.3 local 192.0.2.1 ttl inherit pmtudisc addrgenmode eui64 numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535 tso_max_size 65536 tso_max_segs 65535 gro_max_size 65536
+7: sit1@NONE: <POINTOPOINT,NOARP,UP,LOWER_UP> mtu 1480 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/sit 192.0.2.1 peer 198.51.100.4 promiscuity 0  allmulti 0 minmtu 1280 maxmtu 65555
+    sit ip6ip remote 198.51.100.4 local 192.0.2.1 ttl 255 pmtudisc 6rd-prefix 2002::/16 addrgenmode eui64 numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535 tso_max_size 65536 tso_max_segs 65535 gro_max_size 65536
+    inet6 2001:db8::1/64 scope global
+       valid_lft forever preferred_lft forever
+`
+
+func TestParseAdaptersTunnels(t *testing.T) {
+	adapters := ParseAdapters(ipDetailsOutput)
+	if len(adapters) != 7 {
+		t.Fatalf("There should be 7 adapters present, got %d", len(adapters))
+	}
+
+	gre := adapters.GetByName("gre1")
+	if gre == nil || gre.TunnelInfo == nil {
+		t.Fatalf("Expected gre1 to be parsed as a tunnel")
+	}
+	if gre.Type != "gre" || gre.TunnelInfo.Remote != "198.51.100.1" || gre.TunnelInfo.Local != "192.0.2.1" {
+		t.Errorf("Unexpected gre1 tunnel %+v", gre.TunnelInfo)
+	}
+	if gre.TunnelInfo.Ttl != 64 || !gre.TunnelInfo.PmtuDisc {
+		t.Errorf("Expected gre1 ttl 64 with pmtudisc, got %+v", gre.TunnelInfo)
+	}
+	if gre.TunnelInfo.Key == nil || *gre.TunnelInfo.Key != 42 {
+		t.Errorf("Expected gre1 key 42, got %v", gre.TunnelInfo.Key)
+	}
+
+	gretap := adapters.GetByName("gretap1")
+	if gretap == nil || gretap.TunnelInfo == nil {
+		t.Fatalf("Expected gretap1 to be parsed as a tunnel")
+	}
+	if gretap.Type != "gretap" || gretap.TunnelInfo.Local != "" || gretap.TunnelInfo.Ttl != 0 || gretap.TunnelInfo.PmtuDisc {
+		t.Errorf("Unexpected gretap1 tunnel %+v", gretap.TunnelInfo)
+	}
+	if gretap.DesignatedBridge == nil || adapters.GetIsBridgeId(*gretap.DesignatedBridge).Name != "br0" {
+		t.Errorf("Expected gretap1 to be a member of br0")
+	}
+
+	ipip := adapters.GetByName("ipip1")
+	if ipip == nil || ipip.Type != "ipip" || ipip.TunnelInfo.Remote != "198.51.100.3" || ipip.TunnelInfo.Key != nil {
+		t.Errorf("Unexpected ipip1 %+v", ipip)
+	}
+
+	sit := adapters.GetByName("sit1")
+	if sit == nil || sit.Type != "sit" || sit.TunnelInfo.Ttl != 255 {
+		t.Errorf("Unexpected sit1 %+v", sit)
+	}
+}
+
+func TestParseAdaptersLink(t *testing.T) {
+	adapters := ParseAdapters(ipDetailsOutput)
+	eth0 := adapters.GetByName("eth0")
+	if eth0.Mtu != 1500 || eth0.TxQueueLen != 1000 || eth0.Group != "default" {
+		t.Errorf("Unexpected eth0 link settings %+v", eth0)
+	}
+	if eth0.Alias != "uplink to core" {
+		t.Errorf("Expected eth0 alias 'uplink to core', got %q", eth0.Alias)
+	}
+	if len(eth0.IPv4) != 1 || eth0.MAC != "52:54:00:12:34:56" {
+		t.Errorf("Expected the alias not to affect eth0 addresses %+v", eth0)
+	}
+	if br0 := adapters.GetByName("br0"); br0.Mtu != 1462 || br0.Alias != "" {
+		t.Errorf("Unexpected br0 link settings %+v", br0)
+	}
+}
+
+func TestParseAdaptersBridge(t *testing.T) {
+	adapters := ParseAdapters(ipDetailsOutput)
+	bridge := adapters.GetByName("br0")
+	if bridge == nil || bridge.BridgeInfo == nil {
+		t.Fatalf("Expected br0 to be parsed as a bridge")
+	}
+	if bridge.BridgeInfo.VlanFiltering || bridge.BridgeInfo.VlanDefaultPvid != 1 || bridge.BridgeInfo.VlanProtocol != "802.1Q" {
+		t.Errorf("Unexpected br0 vlan settings %+v", bridge.BridgeInfo)
+	}
+}
+
+func TestParseAdaptersBridgePort(t *testing.T) {
+	adapters := ParseAdapters(ipDetailsOutput)
+	port := adapters.GetByName("gretap1").BridgePortInfo
+	if port == nil {
+		t.Fatalf("Expected gretap1 to have bridge port settings")
+	}
+	if port.Cost != 100 || port.Priority != 32 {
+		t.Errorf("Unexpected cost or priority %+v", port)
+	}
+	if port.Hairpin || !port.Learning || !port.Flood || !port.McastFlood || port.NeighSuppress || port.Isolated || port.BpduGuard {
+		t.Errorf("Unexpected port flags %+v", port)
+	}
+	if adapters.GetByName("br0").BridgePortInfo != nil {
+		t.Errorf("Expected no port settings on the bridge itself")
+	}
+}
+
+func TestParseAdaptersBridgeTuning(t *testing.T) {
+	adapters := ParseAdapters(ipDetailsOutput)
+	info := adapters.GetByName("br0").BridgeInfo
+	if info.Stp || info.ForwardDelay != 1500 || info.HelloTime != 200 || info.MaxAge != 2000 || info.AgeingTime != 30000 {
+		t.Errorf("Unexpected br0 stp settings %+v", info)
+	}
+	if info.Priority != 32768 || info.GroupFwdMask != 0 {
+		t.Errorf("Unexpected br0 priority or group_fwd_mask %+v", info)
+	}
+	if !info.McastSnooping || info.McastQuerier || info.McastIgmpVersion != 2 || info.McastMldVersion != 1 {
+		t.Errorf("Unexpected br0 multicast settings %+v", info)
+	}
+}
+
+var vxlanDetailsOutput string = `8: vxlan100: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1450 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/ether 6a:1b:2c:3d:4e:5f brd ff:ff:ff:ff:ff:ff promiscuity 0  allmulti 0 minmtu 68 maxmtu 65535
+    vxlan id 100 remote 198.51.100.7 local 192.0.2.1 dev eth0 srcport 49152 65535 dstport 4789 nolearning ttl 64 tos inherit ageing 300 udpcsum noudp6zerocsumtx noudp6zerocsumrx addrgenmode eui64 numtxqueues 1 numrxqueues 1
+9: vxlan42: <BROADCAST,MULTICAST> mtu 1450 qdisc noop state DOWN group default qlen 1000
+    link/ether 6a:1b:2c:3d:4e:60 brd ff:ff:ff:ff:ff:ff promiscuity 0  allmulti 0 minmtu 68 maxmtu 65535
+    vxlan id 42 group 239.1.1.42 dev eth0 srcport 0 0 dstport 8472 ttl auto ageing 300 noudpcsum noudp6zerocsumtx noudp6zerocsumrx addrgenmode eui64 numtxqueues 1 numrxqueues 1
+10: vxlan0: <BROADCAST,MULTICAST> mtu 1500 qdisc noop state DOWN group default qlen 1000
+    link/ether 6a:1b:2c:3d:4e:61 brd ff:ff:ff:ff:ff:ff promiscuity 0  allmulti 0 minmtu 68 maxmtu 65535
+    vxlan external id 0 srcport 0 0 dstport 4789 ttl auto ageing 300 udpcsum noudp6zerocsumtx noudp6zerocsumrx addrgenmode eui64 numtxqueues 1 numrxqueues 1
+`
+
+func TestParseAdaptersVxlan(t *testing.T) {
+	adapters := ParseAdapters(vxlanDetailsOutput)
+	if len(adapters) != 3 {
+		t.Fatalf("There should be 3 adapters present, got %d", len(adapters))
+	}
+
+	unicast := adapters.GetByName("vxlan100")
+	if unicast == nil || unicast.VxlanInfo == nil || unicast.Type != "vxlan" {
+		t.Fatalf("Expected vxlan100 to be parsed as a vxlan")
+	}
+	info := unicast.VxlanInfo
+	if *unicast.Vni != 100 || *unicast.Port != 4789 {
+		t.Errorf("Unexpected vxlan100 vni or port %d %d", *unicast.Vni, *unicast.Port)
+	}
+	if info.Remote != "198.51.100.7" || info.Local != "192.0.2.1" || info.Group != "" || info.Dev != "eth0" {
+		t.Errorf("Unexpected vxlan100 addresses %+v", info)
+	}
+	if info.Ttl != 64 || info.Tos != 1 || info.Learning || !info.UdpCsum || info.External {
+		t.Errorf("Unexpected vxlan100 flags %+v", info)
+	}
+	if info.SrcPortMin != 49152 || info.SrcPortMax != 65535 {
+		t.Errorf("Unexpected vxlan100 source ports %+v", info)
+	}
+
+	multicast := adapters.GetByName("vxlan42").VxlanInfo
+	if multicast.Group != "239.1.1.42" || multicast.Remote != "" || multicast.Ttl != 0 || !multicast.Learning || multicast.UdpCsum {
+		t.Errorf("Unexpected vxlan42 settings %+v", multicast)
+	}
+	if multicast.SrcPortMin != 0 || multicast.SrcPortMax != 0 {
+		t.Errorf("Unexpected vxlan42 source ports %+v", multicast)
+	}
+
+	external := adapters.GetByName("vxlan0")
+	if external.VxlanInfo == nil || !external.VxlanInfo.External || *external.Vni != 0 {
+		t.Errorf("Expected vxlan0 to be an external vxlan %+v", external.VxlanInfo)
+	}
+}
+
+func TestParseAdaptersKinds(t *testing.T) {
+	output := `8: veth0@veth1: <BROADCAST,MULTICAST,M-DOWN> mtu 1500 qdisc noop state DOWN group default qlen 1000
+    link/ether 9a:1e:00:11:22:33 brd ff:ff:ff:ff:ff:ff promiscuity 0  allmulti 0 minmtu 68 maxmtu 65535
+    veth addrgenmode eui64 numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535
+9: wg0: <POINTOPOINT,NOARP,UP,LOWER_UP> mtu 1420 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/none  promiscuity 0  allmulti 0 minmtu 0 maxmtu 2147483552
+    wireguard addrgenmode none numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535
+10: dummy0: <BROADCAST,NOARP,UP,LOWER_UP> mtu 1500 qdisc noqueue state UNKNOWN group default qlen 1000
+    link/ether 6e:4d:12:34:56:78 brd ff:ff:ff:ff:ff:ff promiscuity 0  allmulti 0 minmtu 0 maxmtu 0
+    dummy addrgenmode eui64 numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535
+11: tun0: <POINTOPOINT,MULTICAST,NOARP,UP,LOWER_UP> mtu 1500 qdisc fq_codel state UNKNOWN group default qlen 500
+    link/none  promiscuity 0  allmulti 0 minmtu 68 maxmtu 65535
+    tun type tun pi off vnet_hdr off persist off addrgenmode random numtxqueues 1 numrxqueues 1 gso_max_size 65536 gso_max_segs 65535
+`
+	adapters := ParseAdapters(output)
+	if veth := adapters.GetByName("veth0"); veth == nil || veth.Type != "veth" || *veth.LinkedInterface != "veth1" {
+		t.Errorf("expected veth0 to be a veth linked to veth1, got %+v", veth)
+	}
+	if wg := adapters.GetByName("wg0"); wg == nil || wg.Type != "wireguard" {
+		t.Errorf("expected wg0 to be a WireGuard interface, got %+v", wg)
+	}
+	if dummy := adapters.GetByName("dummy0"); dummy == nil || dummy.Type != "dummy" {
+		t.Errorf("expected dummy0 to be a dummy interface, got %+v", dummy)
+	}
+	if tun := adapters.GetByName("tun0"); tun == nil || tun.Type != "unknown" {
+		t.Errorf("expected the NOARP tun0 not to be a dummy interface, got %+v", tun)
+	}
+}
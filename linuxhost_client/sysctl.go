@@ -0,0 +1,89 @@
+package linuxhost_client
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const sysctlMissing = "### missing"
+
+var sysctlFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// SysctlFile is the file below /etc/sysctl.d persisting a key
+func SysctlFile(key string) string {
+	return "/etc/sysctl.d/90-linuxhost-" + sysctlFileChars.ReplaceAllString(key, "_") + ".conf"
+}
+
+// RenderSysctlFile renders the sysctl.d file persisting key
+func RenderSysctlFile(key string, value string) string {
+	return fmt.Sprintf("# Managed by terraform-provider-linuxhost\n%s = %s\n", key, value)
+}
+
+// NormalizeSysctlValue joins the fields of multi-value keys like
+// net.ipv4.ip_local_port_range, which sysctl separates with tabs, by spaces
+func NormalizeSysctlValue(value string) string {
+	return strings.Join(strings.Fields(value), " ")
+}
+
+// SetSysctl sets the runtime value of key
+func SetSysctl(connectedClient *SSHClientContext, key string, value string) error {
+	_, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo sysctl -w %s", shellQuote(key+"="+value)))
+	return err
+}
+
+// ReadSysctl returns the runtime value of key, nil if the key doesn't exist
+func ReadSysctl(connectedClient *SSHClientContext, key string) (*string, error) {
+	result, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo sysctl -n %s 2>/dev/null || echo '%s'", shellQuote(key), sysctlMissing))
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(result) == sysctlMissing {
+		return nil, nil
+	}
+	value := NormalizeSysctlValue(result)
+	return &value, nil
+}
+
+// PersistSysctl writes the sysctl.d file of key
+func PersistSysctl(connectedClient *SSHClientContext, key string, value string) error {
+	return writePersistedFiles(connectedClient, PersistedFiles{SysctlFile(key): RenderSysctlFile(key, value)})
+}
+
+// UnpersistSysctl removes the sysctl.d file of key
+func UnpersistSysctl(connectedClient *SSHClientContext, key string) error {
+	return removePersistedFiles(connectedClient, PersistedFiles{SysctlFile(key): ""})
+}
+
+// ReadPersistedSysctl returns the value of key in its sysctl.d file, nil if
+// the file doesn't exist or doesn't set it
+func ReadPersistedSysctl(connectedClient *SSHClientContext, key string) (*string, error) {
+	content, err := connectedClient.ExecuteCommand(fmt.Sprintf("sudo cat %s 2>/dev/null || true", shellQuote(SysctlFile(key))))
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := ParseSysctlConf(content)[key]; ok {
+		return &value, nil
+	}
+	return nil, nil
+}
+
+// ParseSysctlConf parses the "key = value" lines of a sysctl.d file, the
+// values normalized like the runtime ones
+func ParseSysctlConf(content string) map[string]string {
+	values := map[string]string{}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		// A leading '-' makes systemd-sysctl ignore failures of the key
+		key = strings.TrimPrefix(strings.TrimSpace(key), "-")
+		values[key] = NormalizeSysctlValue(value)
+	}
+	return values
+}
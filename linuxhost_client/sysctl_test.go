@@ -0,0 +1,28 @@
+package linuxhost_client
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSysctlConf(t *testing.T) {
+	values := ParseSysctlConf(RenderSysctlFile("net.ipv4.ip_local_port_range", "1024 65000") + `
+; another comment
+-net.bridge.bridge-nf-call-iptables=1
+net/ipv4/conf/eth0.100/rp_filter =	2
+`)
+	expected := map[string]string{
+		"net.ipv4.ip_local_port_range":       "1024 65000",
+		"net.bridge.bridge-nf-call-iptables": "1",
+		"net/ipv4/conf/eth0.100/rp_filter":   "2",
+	}
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Expected %v, got %v", expected, values)
+	}
+	if v := NormalizeSysctlValue("32768\t60999\n"); v != "32768 60999" {
+		t.Errorf("Expected the fields separated by a space, got %q", v)
+	}
+	if f := SysctlFile("net/ipv4/conf/eth0.100/rp_filter"); f != "/etc/sysctl.d/90-linuxhost-net_ipv4_conf_eth0.100_rp_filter.conf" {
+		t.Errorf("Unexpected file %s", f)
+	}
+}
@@ -11,6 +11,11 @@ type NetworkInterfaceResourceModel struct {
 	ParentInterface types.String `tfsdk:"parent_interface"`
 	VLAN_id         types.Number `tfsdk:"vlan_id"`
 	DHCP            types.String `tfsdk:"dhcp"`
+
+	DhcpAddress     types.String `tfsdk:"dhcp_address"`
+	DhcpGateway     types.String `tfsdk:"dhcp_gateway"`
+	DhcpDnsServers  types.List   `tfsdk:"dhcp_dns_servers"`
+	DhcpLeaseExpiry types.String `tfsdk:"dhcp_lease_expiry"`
 }
 
 func (r *NetworkInterfaceResourceModel) UpString() string {
@@ -29,9 +34,26 @@ type IfCommonResourceModel struct {
 	IP4s   types.Set                    `tfsdk:"ipv4"`
 	State  types.String                 `tfsdk:"state"`
 	Bridge *IfBridgeMemberResourceModel `tfsdk:"bridge"`
+	Netns  types.String                 `tfsdk:"netns"`
+
+	Persistence types.String `tfsdk:"persistence"`
+
+	Mtu        types.Int32  `tfsdk:"mtu"`
+	TxQueueLen types.Int32  `tfsdk:"txqueuelen"`
+	Alias      types.String `tfsdk:"alias"`
+	Group      types.String `tfsdk:"group"`
 }
 type IfBridgeMemberResourceModel struct {
-	Name types.String `tfsdk:"name"`
+	Name          types.String `tfsdk:"name"`
+	Cost          types.Int32  `tfsdk:"cost"`
+	Priority      types.Int32  `tfsdk:"priority"`
+	Hairpin       types.Bool   `tfsdk:"hairpin"`
+	Learning      types.Bool   `tfsdk:"learning"`
+	Flood         types.Bool   `tfsdk:"flood"`
+	McastFlood    types.Bool   `tfsdk:"mcast_flood"`
+	NeighSuppress types.Bool   `tfsdk:"neigh_suppress"`
+	Isolated      types.Bool   `tfsdk:"isolated"`
+	BpduGuard     types.Bool   `tfsdk:"bpdu_guard"`
 }
 
 type IsIfResourceModel interface {
@@ -41,6 +63,21 @@ type IsIfResourceModel interface {
 // Bridge
 type IfBridgeResourceModel struct {
 	IfCommonResourceModel
+	VlanFiltering   types.Bool   `tfsdk:"vlan_filtering"`
+	VlanDefaultPvid types.Int32  `tfsdk:"vlan_default_pvid"`
+	VlanProtocol    types.String `tfsdk:"vlan_protocol"`
+
+	Stp              types.Bool  `tfsdk:"stp"`
+	ForwardDelay     types.Int32 `tfsdk:"forward_delay"`
+	HelloTime        types.Int32 `tfsdk:"hello_time"`
+	MaxAge           types.Int32 `tfsdk:"max_age"`
+	AgeingTime       types.Int32 `tfsdk:"ageing_time"`
+	Priority         types.Int32 `tfsdk:"priority"`
+	GroupFwdMask     types.Int32 `tfsdk:"group_fwd_mask"`
+	McastSnooping    types.Bool  `tfsdk:"mcast_snooping"`
+	McastQuerier     types.Bool  `tfsdk:"mcast_querier"`
+	McastIgmpVersion types.Int32 `tfsdk:"mcast_igmp_version"`
+	McastMldVersion  types.Int32 `tfsdk:"mcast_mld_version"`
 }
 
 var _ IsIfResourceModel = &IfBridgeResourceModel{}
@@ -49,6 +86,17 @@ func (m *IfBridgeResourceModel) GetCommon() *IfCommonResourceModel {
 	return &m.IfCommonResourceModel
 }
 
+// Dummy
+type IfDummyResourceModel struct {
+	IfCommonResourceModel
+}
+
+var _ IsIfResourceModel = &IfDummyResourceModel{}
+
+func (m *IfDummyResourceModel) GetCommon() *IfCommonResourceModel {
+	return &m.IfCommonResourceModel
+}
+
 // Veth
 type IfVethPeerResourceModel struct {
 	IfCommonResourceModel
@@ -83,6 +131,18 @@ type IfVxlanResourceModel struct {
 	IfCommonResourceModel
 	Vni  types.Int64 `tfsdk:"vni"`
 	Port types.Int32 `tfsdk:"port"`
+
+	Local      types.String `tfsdk:"local"`
+	Remote     types.String `tfsdk:"remote"`
+	Group      types.String `tfsdk:"group"`
+	Dev        types.String `tfsdk:"dev"`
+	Ttl        types.Int32  `tfsdk:"ttl"`
+	Tos        types.Int32  `tfsdk:"tos"`
+	Learning   types.Bool   `tfsdk:"learning"`
+	SrcPortMin types.Int32  `tfsdk:"srcport_min"`
+	SrcPortMax types.Int32  `tfsdk:"srcport_max"`
+	UdpCsum    types.Bool   `tfsdk:"udpcsum"`
+	External   types.Bool   `tfsdk:"external"`
 }
 
 var _ IsIfResourceModel = &IfVxlanResourceModel{}
@@ -91,9 +151,179 @@ func (m *IfVxlanResourceModel) GetCommon() *IfCommonResourceModel {
 	return &m.IfCommonResourceModel
 }
 
+// Tunnels: gre, gretap, ipip and sit
+type IfTunnelResourceModel struct {
+	IfCommonResourceModel
+	Local    types.String `tfsdk:"local"`
+	Remote   types.String `tfsdk:"remote"`
+	Ttl      types.Int32  `tfsdk:"ttl"`
+	Key      types.Int64  `tfsdk:"key"`
+	PmtuDisc types.Bool   `tfsdk:"pmtudisc"`
+}
+
+var _ IsIfResourceModel = &IfTunnelResourceModel{}
+
+func (m *IfTunnelResourceModel) GetCommon() *IfCommonResourceModel {
+	return &m.IfCommonResourceModel
+}
+
+// WireGuard
+type IfWireguardResourceModel struct {
+	IfCommonResourceModel
+	ListenPort types.Int32  `tfsdk:"listen_port"`
+	FwMark     types.Int64  `tfsdk:"fwmark"`
+	PrivateKey types.String `tfsdk:"private_key"`
+	PublicKey  types.String `tfsdk:"public_key"`
+}
+
+var _ IsIfResourceModel = &IfWireguardResourceModel{}
+
+func (m *IfWireguardResourceModel) GetCommon() *IfCommonResourceModel {
+	return &m.IfCommonResourceModel
+}
+
+type WireguardPeerModel struct {
+	Interface           types.String `tfsdk:"interface"`
+	Netns               types.String `tfsdk:"netns"`
+	PublicKey           types.String `tfsdk:"public_key"`
+	PresharedKey        types.String `tfsdk:"preshared_key"`
+	AllowedIPs          types.Set    `tfsdk:"allowed_ips"`
+	Endpoint            types.String `tfsdk:"endpoint"`
+	PersistentKeepalive types.Int32  `tfsdk:"persistent_keepalive"`
+}
+
+type BridgeVlanModel struct {
+	Port     types.String `tfsdk:"port"`
+	Vid      types.Int32  `tfsdk:"vid"`
+	VidEnd   types.Int32  `tfsdk:"vid_end"`
+	Pvid     types.Bool   `tfsdk:"pvid"`
+	Untagged types.Bool   `tfsdk:"untagged"`
+	Self     types.Bool   `tfsdk:"self"`
+}
+
+type BridgeFdbModel struct {
+	Mac    types.String `tfsdk:"mac"`
+	Dev    types.String `tfsdk:"dev"`
+	Dst    types.String `tfsdk:"dst"`
+	Vni    types.Int32  `tfsdk:"vni"`
+	Port   types.Int32  `tfsdk:"port"`
+	Master types.Bool   `tfsdk:"master"`
+	Self   types.Bool   `tfsdk:"self"`
+	State  types.String `tfsdk:"state"`
+}
+
+type NeighborModel struct {
+	Address types.String `tfsdk:"address"`
+	Dev     types.String `tfsdk:"dev"`
+	Lladdr  types.String `tfsdk:"lladdr"`
+	State   types.String `tfsdk:"state"`
+	Proxy   types.Bool   `tfsdk:"proxy"`
+}
+
+type EthtoolModel struct {
+	Dev      types.String          `tfsdk:"dev"`
+	Features map[string]types.Bool `tfsdk:"features"`
+	Ring     *EthtoolRingModel     `tfsdk:"ring"`
+	Channels *EthtoolChannelsModel `tfsdk:"channels"`
+	Coalesce *EthtoolCoalesceModel `tfsdk:"coalesce"`
+	Speed    types.Int32           `tfsdk:"speed"`
+	Duplex   types.String          `tfsdk:"duplex"`
+	Autoneg  types.Bool            `tfsdk:"autoneg"`
+}
+type EthtoolRingModel struct {
+	Rx      types.Int32 `tfsdk:"rx"`
+	RxMini  types.Int32 `tfsdk:"rx_mini"`
+	RxJumbo types.Int32 `tfsdk:"rx_jumbo"`
+	Tx      types.Int32 `tfsdk:"tx"`
+}
+type EthtoolChannelsModel struct {
+	Rx       types.Int32 `tfsdk:"rx"`
+	Tx       types.Int32 `tfsdk:"tx"`
+	Other    types.Int32 `tfsdk:"other"`
+	Combined types.Int32 `tfsdk:"combined"`
+}
+type EthtoolCoalesceModel struct {
+	AdaptiveRx types.Bool  `tfsdk:"adaptive_rx"`
+	AdaptiveTx types.Bool  `tfsdk:"adaptive_tx"`
+	RxUsecs    types.Int32 `tfsdk:"rx_usecs"`
+	RxFrames   types.Int32 `tfsdk:"rx_frames"`
+	TxUsecs    types.Int32 `tfsdk:"tx_usecs"`
+	TxFrames   types.Int32 `tfsdk:"tx_frames"`
+}
+
+type SysctlModel struct {
+	Key     types.String `tfsdk:"key"`
+	Value   types.String `tfsdk:"value"`
+	Persist types.Bool   `tfsdk:"persist"`
+}
+
+type NftablesTableModel struct {
+	Family types.String `tfsdk:"family"`
+	Name   types.String `tfsdk:"name"`
+}
+
+type NftablesChainModel struct {
+	Family   types.String `tfsdk:"family"`
+	Table    types.String `tfsdk:"table"`
+	Name     types.String `tfsdk:"name"`
+	Type     types.String `tfsdk:"type"`
+	Hook     types.String `tfsdk:"hook"`
+	Priority types.Int32  `tfsdk:"priority"`
+	Policy   types.String `tfsdk:"policy"`
+	Device   types.String `tfsdk:"device"`
+}
+
+type NftablesRuleModel struct {
+	Family  types.String `tfsdk:"family"`
+	Table   types.String `tfsdk:"table"`
+	Chain   types.String `tfsdk:"chain"`
+	Rule    types.String `tfsdk:"rule"`
+	Insert  types.Bool   `tfsdk:"insert"`
+	Comment types.String `tfsdk:"comment"`
+	Handle  types.Int64  `tfsdk:"handle"`
+}
+
+type IptablesRuleModel struct {
+	Table    types.String `tfsdk:"table"`
+	Chain    types.String `tfsdk:"chain"`
+	Rule     types.String `tfsdk:"rule"`
+	Position types.Int32  `tfsdk:"position"`
+	Ipv6     types.Bool   `tfsdk:"ipv6"`
+	Comment  types.String `tfsdk:"comment"`
+}
+
+type TcQdiscModel struct {
+	Dev     types.String `tfsdk:"dev"`
+	Parent  types.String `tfsdk:"parent"`
+	Handle  types.String `tfsdk:"handle"`
+	Kind    types.String `tfsdk:"kind"`
+	Options types.String `tfsdk:"options"`
+}
+
+type TcClassModel struct {
+	Dev     types.String `tfsdk:"dev"`
+	Parent  types.String `tfsdk:"parent"`
+	ClassId types.String `tfsdk:"classid"`
+	Kind    types.String `tfsdk:"kind"`
+	Options types.String `tfsdk:"options"`
+}
+
+type HostsEntryModel struct {
+	Ip        types.String `tfsdk:"ip"`
+	Hostnames types.List   `tfsdk:"hostnames"`
+	Comment   types.String `tfsdk:"comment"`
+}
+
+type NetnsModel struct {
+	Name       types.String `tfsdk:"name"`
+	Nsid       types.Int32  `tfsdk:"nsid"`
+	Interfaces types.Set    `tfsdk:"interfaces"`
+}
+
 type NetowrkInterfaceIPAssignmentModel struct {
 	InterfaceName types.String `tfsdk:"interface_name"`
 	IPv4          types.String `tfsdk:"ipv4"`
+	Persistence   types.String `tfsdk:"persistence"`
 }
 
 type UserModel struct {
@@ -120,3 +350,44 @@ type CaCertificateModel struct {
 	Source            types.String `tfsdk:"source"`
 	FingerprintSha256 types.String `tfsdk:"fingerprint_sha256"`
 }
+
+// Data sources
+
+type NetworkInterfaceDataModel struct {
+	Name   types.String                    `tfsdk:"name"`
+	Mac    types.String                    `tfsdk:"mac"`
+	Type   types.String                    `tfsdk:"type"`
+	Mtu    types.Int32                     `tfsdk:"mtu"`
+	State  types.String                    `tfsdk:"state"`
+	IP4s   types.List                      `tfsdk:"ipv4"`
+	IP6s   types.List                      `tfsdk:"ipv6"`
+	Parent types.String                    `tfsdk:"parent"`
+	Bridge types.String                    `tfsdk:"bridge"`
+	Vlan   *NetworkInterfaceVlanDataModel  `tfsdk:"vlan"`
+	Vxlan  *NetworkInterfaceVxlanDataModel `tfsdk:"vxlan"`
+}
+type NetworkInterfaceVlanDataModel struct {
+	Id     types.Int32  `tfsdk:"id"`
+	Parent types.String `tfsdk:"parent"`
+}
+type NetworkInterfaceVxlanDataModel struct {
+	Vni      types.Int64  `tfsdk:"vni"`
+	Port     types.Int32  `tfsdk:"port"`
+	Local    types.String `tfsdk:"local"`
+	Remote   types.String `tfsdk:"remote"`
+	Group    types.String `tfsdk:"group"`
+	Dev      types.String `tfsdk:"dev"`
+	Learning types.Bool   `tfsdk:"learning"`
+}
+
+type SysctlDataModel struct {
+	Key   types.String `tfsdk:"key"`
+	Value types.String `tfsdk:"value"`
+}
+
+type NetworkInterfacesDataModel struct {
+	Type       types.String                `tfsdk:"type"`
+	State      types.String                `tfsdk:"state"`
+	NameRegex  types.String                `tfsdk:"name_regex"`
+	Interfaces []NetworkInterfaceDataModel `tfsdk:"interfaces"`
+}